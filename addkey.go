@@ -0,0 +1,156 @@
+package perfect
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+)
+
+// AddResult is the outcome of adding a key to a lookup with [HashFinder.AddKey].
+type AddResult struct {
+	Lookup   *Lookup // Lookup of the old keys followed by the new key.
+	Moved    bool    // Whether any of the old keys changed slot.
+	Attempts int     // Number of hash configurations tried.
+}
+
+// AddKey returns a lookup of the keys of l followed by key, changing the hash of l
+// as little as possible. If key lands on a free slot the hash and table are kept as-is.
+// Otherwise coefficients of l's [HashSequential] are changed one at a time, closest
+// values first, and only if that fails is a full search run over the coefficient ranges
+// of the hash, first in the same table size and then in one twice as large.
+// l is not modified.
+func (phf *HashFinder) AddKey(l *Lookup, key string) (AddResult, error) {
+	if _, ok := l.Find(key); ok {
+		return AddResult{}, errors.New("duplicate key " + key)
+	}
+	keys := append(slices.Clip(l.keys), key)
+	tableSizeBits := bits.Len(l.mask)
+	if l.slots[l.hasher.Hash(key)&l.mask] == 0 {
+		added, err := NewLookup(l.hasher, tableSizeBits, keys)
+		return AddResult{Lookup: added}, err
+	}
+	hs, ok := l.hasher.(*HashSequential)
+	if !ok {
+		return AddResult{}, fmt.Errorf("hash %T can not be searched for new key", l.hasher)
+	}
+	mask, err := phf.init(tableSizeBits, keys)
+	if err != nil {
+		return AddResult{}, err
+	}
+	var result AddResult
+	var found *HashSequential
+	full := len(keys) > len(l.slots)
+	if !full {
+		found = phf.searchNeighbours(hs, mask, keys, &result.Attempts)
+	}
+	if found == nil {
+		grow := 0
+		if full {
+			grow = 1 // No room for the key in the current table.
+		}
+		for ; grow < 2; grow++ {
+			found = hs.Clone().(*HashSequential)
+			err = found.ConfigCoefs(addKeyMaxCoef)
+			if err != nil {
+				return result, err
+			}
+			var attempts int
+			attempts, err = phf.Search(found, tableSizeBits+grow, keys)
+			result.Attempts += attempts
+			if err == nil {
+				tableSizeBits += grow
+				break
+			} else if !errors.Is(err, ErrNoCoefficientsFound) {
+				return result, err
+			}
+		}
+		if err != nil {
+			return result, err
+		}
+	}
+	result.Lookup, err = NewLookup(found, tableSizeBits, keys)
+	if err != nil {
+		return result, err
+	}
+	oldSlots := l.keySlots()
+	newSlots := result.Lookup.keySlots()
+	result.Moved = !slices.Equal(oldSlots, newSlots[:len(oldSlots)])
+	return result, nil
+}
+
+// addKeyMaxCoef is the coefficient range searched around and beyond unbounded coefficients,
+// such as those of a parsed hash.
+const addKeyMaxCoef = 32
+
+// searchNeighbours returns a copy of hs with a single coefficient value changed which is
+// perfect for keys, or nil if there is none. Values closest to the current one are tried first,
+// see [Coef.neighbour].
+func (phf *HashFinder) searchNeighbours(hs *HashSequential, mask uint, keys []string, attempts *int) *HashSequential {
+	candidate := hs.Clone().(*HashSequential)
+	m := newKeyMatrix(keys, coefPositions(candidate, 0))
+	coefs := make([]*Coef, 0, len(candidate.Coefs)+1)
+	coefs = append(coefs, &candidate.LenCoef)
+	for i := range candidate.Coefs {
+		coefs = append(coefs, &candidate.Coefs[i])
+	}
+	maxDist := uint(0)
+	limits := make([]uint, len(coefs))
+	for i, c := range coefs {
+		limits[i] = max(c.MaxValue, 2*c.Value, addKeyMaxCoef)
+		maxDist = max(maxDist, limits[i])
+	}
+	for dist := uint(1); dist < maxDist; dist++ {
+		for i, c := range coefs {
+			original := c.Value
+			for _, up := range [2]bool{true, false} {
+				v, ok := c.neighbour(original, dist, up)
+				if !ok || v >= limits[i] {
+					continue
+				}
+				*attempts++
+				c.Value = v
+				if phf.perfect(candidate, m, mask) {
+					return candidate
+				}
+			}
+			c.Value = original
+		}
+	}
+	return nil
+}
+
+// neighbour returns the value dist steps above or below v in c's search space: steps
+// double or halve the value of an OnlyPow2 coefficient and add or subtract one otherwise.
+// It returns false if the value wraps around or leaves the search space below.
+func (c *Coef) neighbour(v, dist uint, up bool) (uint, bool) {
+	if !c.OnlyPow2 {
+		if up {
+			return v + dist, v+dist > v
+		}
+		return v - dist, v > dist
+	}
+	if dist >= bits.UintSize {
+		return 0, false
+	} else if up {
+		n := v << dist
+		return n, n>>dist == v
+	}
+	n := v >> dist
+	return n, n<<dist == v && n >= max(c.StartValue, 1)
+}
+
+// perfect reports whether hs maps the inputs of m to distinct slots of the finder's table.
+func (phf *HashFinder) perfect(hs *HashSequential, m *keyMatrix, mask uint) bool {
+	hashmap := phf.hashmap
+	epoch := phf.nextEpoch()
+	phf.cols = m.columns(hs, phf.cols)
+	for k := range m.lens {
+		h := uint(m.hash(hs, phf.cols, k)) & mask
+		if hashmap[h] == epoch {
+			return false
+		}
+		hashmap[h] = epoch
+	}
+	return true
+}
@@ -0,0 +1,84 @@
+package perfect
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+)
+
+func ExampleHashFinder_AddKey() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	// Hash found by ExampleHashFinder_goKeywords.
+	hasher, err := ParseHashSequential("h := uint(len(s))*8; h ^= uint(s[0])*1; h ^= uint(s[1])*8")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	lookup, err := NewLookup(hasher, 6, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	for _, kw := range []string{"any", "iota", "nil", "true"} {
+		result, err := phf.AddKey(lookup, kw)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		lookup = result.Lookup
+		i, _ := lookup.Find(kw)
+		fmt.Printf("added %s as %d: moved=%v attempts=%d overflow=%v\n", kw, i, result.Moved, result.Attempts, lookup.Overflow())
+	}
+	// Output:
+	// added any as 25: moved=true attempts=49 overflow=[]
+	// added iota as 26: moved=true attempts=335 overflow=[]
+	// added nil as 27: moved=false attempts=0 overflow=[]
+	// added true as 28: moved=true attempts=60 overflow=[]
+}
+
+func ExampleHashFinder_AddKey_pow2() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	hasher := &HashSequential{
+		LenCoef: Coef{OnlyPow2: true},
+		Coefs: []Coef{
+			{IndexApplied: 0, OnlyPow2: true, Op: OpXor},
+			{IndexApplied: 1, OnlyPow2: true, Op: OpXor},
+		},
+	}
+	err := hasher.ConfigCoefs(64)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	const tablesizebits = 6
+	_, err = phf.Search(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	lookup, err := NewLookup(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Coefficients of power of two values are only moved to other powers of two.
+	for _, kw := range []string{"any", "iota", "nil"} {
+		result, err := phf.AddKey(lookup, kw)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		lookup = result.Lookup
+		hs := lookup.hasher.(*HashSequential)
+		fmt.Printf("added %s: values %d %d %d\n", kw, hs.LenCoef.Value, hs.Coefs[0].Value, hs.Coefs[1].Value)
+	}
+	// Output:
+	// added any: values 1 32 1
+	// added iota: values 4 32 1
+	// added nil: values 1 8 16
+}
@@ -0,0 +1,134 @@
+package perfect
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+)
+
+// Anneal configures a local search over [HashSequential] configurations. See [HashFinder.SearchAnneal].
+type Anneal struct {
+	// Steps is the maximum number of configurations scored. Defaults to 100000.
+	Steps int
+	// Temperature is the initial temperature in collisions, which decreases linearly to
+	// zero over Steps. A move adding d collisions is accepted with probability exp(-d/T).
+	// Zero is hill climbing: only moves which do not add collisions are accepted.
+	Temperature float64
+	// MaxValue bounds coefficient values to [1, MaxValue). Defaults to 64.
+	MaxValue uint
+	// MaxIndex, if positive, lets moves change a coefficient's IndexApplied to
+	// any index in [-MaxIndex, MaxIndex). Zero keeps indices fixed.
+	MaxIndex int
+	// Seed seeds the random moves so searches are reproducible.
+	Seed uint64
+}
+
+// SearchAnneal searches for a perfect hash by local search starting from the configuration of hs.
+// Unlike [HashFinder.Search] which passes or fails each configuration, every configuration
+// is scored by its number of colliding inputs. Each step makes a random move, changing the
+// value, operation or index of one coefficient, which is kept if it does not add collisions
+// or otherwise with a probability given by the temperature of [Anneal].
+//
+// hs is modified during the search. The result holds the configuration with fewest collisions.
+// Like [HashFinder.SearchBest] it returns [ErrNoCoefficientsFound] with the best configuration
+// and its colliding inputs if no perfect hash was found within cfg.Steps.
+func (phf *HashFinder) SearchAnneal(hs *HashSequential, tableSizeBits int, inputs []string, cfg Anneal) (SearchResult, error) {
+	mask, err := phf.init(tableSizeBits, inputs)
+	if err != nil {
+		return SearchResult{}, err
+	} else if len(hs.Coefs) == 0 {
+		return SearchResult{}, errors.New("hash has no coefficients")
+	} else if cfg.Temperature < 0 {
+		return SearchResult{}, errors.New("negative temperature")
+	}
+	for i := range hs.Coefs {
+		if !hs.Coefs[i].Op.valid() {
+			return SearchResult{}, errors.New("coefficient has unknown operation, see ConfigCoefs")
+		}
+	}
+	steps := cfg.Steps
+	if steps <= 0 {
+		steps = 100000
+	}
+	maxValue := cfg.MaxValue
+	if maxValue == 0 {
+		maxValue = 64
+	} else if maxValue < 2 {
+		return SearchResult{}, errors.New("MaxValue must be at least 2")
+	}
+	rng := rand.New(rand.NewPCG(cfg.Seed, 0x9e3779b97f4a7c15))
+	m := newKeyMatrix(inputs, coefPositions(hs, cfg.MaxIndex))
+	current := phf.countCollisions(hs, m, mask)
+	best := current
+	result := SearchResult{Best: hs.Clone()}
+	for step := 0; step < steps && best > 0; step++ {
+		result.Attempts++
+		k := rng.IntN(len(hs.Coefs) + 1)
+		c := &hs.LenCoef
+		if k > 0 {
+			c = &hs.Coefs[k-1]
+		}
+		saved := *c
+		mutateCoef(c, rng, k == 0, maxValue, cfg.MaxIndex)
+		score := phf.countCollisions(hs, m, mask)
+		accept := score <= current
+		if !accept && cfg.Temperature > 0 {
+			temperature := cfg.Temperature * (1 - float64(step)/float64(steps))
+			accept = temperature > 0 && rng.Float64() < math.Exp(-float64(score-current)/temperature)
+		}
+		if !accept {
+			*c = saved
+			continue
+		}
+		current = score
+		if score < best {
+			best = score
+			result.Best = hs.Clone()
+		}
+	}
+	if best > 0 {
+		result.Collisions = phf.collisions(result.Best, mask, inputs)
+		return result, ErrNoCoefficientsFound
+	}
+	return result, nil
+}
+
+// mutateCoef makes a random move of c: changing its operation, its index if maxIndex
+// is positive, or its value within [1, maxValue). The operation and index of the
+// length coefficient are never changed.
+func mutateCoef(c *Coef, rng *rand.Rand, isLen bool, maxValue uint, maxIndex int) {
+	switch move := rng.IntN(4); {
+	case move == 0 && !isLen:
+		c.Op = ops[(int(c.Op)+rng.IntN(len(ops)-1))%len(ops)] // A different operation.
+	case move == 1 && !isLen && maxIndex > 0:
+		c.IndexApplied = rng.IntN(2*maxIndex) - maxIndex
+	case move == 2:
+		c.Value = 1 + uint(rng.Uint64N(uint64(maxValue-1)))
+	default:
+		// Small step so good configurations are refined rather than replaced.
+		delta := 1 + uint(rng.IntN(3))
+		if rng.IntN(2) == 0 && c.Value > delta {
+			c.Value -= delta
+		} else if c.Value+delta < maxValue {
+			c.Value += delta
+		}
+	}
+}
+
+// ops are the operations a coefficient may take, indexed by Op-1.
+var ops = [...]Op{OpAdd, OpXor, OpMul}
+
+// countCollisions returns the number of inputs of m that land on a slot occupied by an earlier input.
+func (phf *HashFinder) countCollisions(hs *HashSequential, m *keyMatrix, mask uint) (n int) {
+	hashmap := phf.hashmap
+	epoch := phf.nextEpoch()
+	phf.cols = m.columns(hs, phf.cols)
+	for k := range m.lens {
+		h := uint(m.hash(hs, phf.cols, k)) & mask
+		if hashmap[h] == epoch {
+			n++
+		}
+		hashmap[h] = epoch
+	}
+	return n
+}
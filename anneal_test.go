@@ -0,0 +1,38 @@
+package perfect
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+)
+
+func ExampleHashFinder_SearchAnneal() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	hasher := &HashSequential{
+		Coefs: []Coef{{IndexApplied: 0}, {IndexApplied: 1}, {IndexApplied: 2}, {IndexApplied: -1}},
+	}
+	err := hasher.ConfigCoefs(32)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	// 25 keywords in 32 slots.
+	result, err := phf.SearchAnneal(hasher, 5, keywords, Anneal{Temperature: 1, MaxValue: 32, MaxIndex: 3, Seed: 2})
+	if err != nil {
+		log.Fatalln(err, "with", len(result.Collisions), "collisions")
+	}
+	fmt.Println("attempts:", result.Attempts)
+	fmt.Print(result.Best.(*HashSequential).String())
+	// Output:
+	// attempts: 62845
+	// h := uint(len(s))*11
+	// h += uint(s[1])*8
+	// h += uint(s[len(s)-2])*24
+	// h += uint(s[len(s)-3])*31
+	// h ^= uint(s[2])*12
+}
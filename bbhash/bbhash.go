@@ -0,0 +1,244 @@
+// Package bbhash implements a space-efficient minimal perfect hash for large static
+// key sets using the BBHash cascade of bit vectors.
+//
+// Keys are hashed into a bit vector of gamma times as many bits as keys. Keys which land
+// on a bit no other key lands on are resolved at that level, the remaining keys cascade
+// down to a smaller bit vector on the next level. A key's index is the rank of its bit
+// over all levels, so the hash maps n keys onto [0, n) using around 3 to 4 bits per key.
+package bbhash
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+
+	"github.com/soypat/perfect"
+)
+
+const (
+	maxLevels = 64
+	maxSeeds  = 8
+	// rankBlockWords is the number of words between rank samples.
+	rankBlockWords = 8
+	binaryMagic    = "BBH1"
+)
+
+var _ perfect.Hash = (*Hash)(nil)
+
+// Hash is a minimal perfect hash built by [New].
+type Hash struct {
+	n      int
+	seed   uint64
+	levels []level
+	words  []uint64 // Bit vectors of all levels, concatenated.
+	ranks  []uint64 // Number of set bits in words before each rank block.
+}
+
+type level struct {
+	offset int    // Word offset of level's bit vector in words.
+	size   uint64 // Size of level's bit vector in bits, a multiple of 64.
+}
+
+// New builds a minimal perfect hash for keys. gamma is the ratio of bits per key on
+// each level of the cascade and trades space for construction and evaluation speed,
+// 2 is a good default; values below 1 are invalid. Keys must be unique.
+func New(keys []string, gamma float64) (*Hash, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("zero inputs")
+	} else if gamma < 1 || math.IsInf(gamma, 0) || math.IsNaN(gamma) {
+		return nil, errors.New("gamma must be finite and at least 1")
+	}
+	var bb Hash
+	hashes := make([]uint64, len(keys))
+	for seed := range uint64(maxSeeds) {
+		for i, kw := range keys {
+			hashes[i] = hash64(kw, seed)
+		}
+		if bb.build(hashes, gamma) {
+			bb.n = len(keys)
+			bb.seed = seed
+			bb.computeRanks()
+			return &bb, nil
+		}
+	}
+	return nil, errors.New("could not build hash, keys likely contain duplicates")
+}
+
+// build cascades hashes down the levels. Reports false if keys remain after the last level.
+func (bb *Hash) build(hashes []uint64, gamma float64) bool {
+	bb.levels = bb.levels[:0]
+	bb.words = bb.words[:0]
+	remaining := hashes
+	var collide []uint64
+	for lvl := 0; lvl < maxLevels && len(remaining) > 0; lvl++ {
+		nwords := int(math.Ceil(gamma * float64(len(remaining)) / 64))
+		size := uint64(nwords) * 64
+		offset := len(bb.words)
+		bb.words = append(bb.words, make([]uint64, nwords)...)
+		collide = append(collide[:0], make([]uint64, nwords)...)
+		set := bb.words[offset:]
+		for _, h := range remaining {
+			i := levelIndex(h, lvl, size)
+			if set[i/64]&(1<<(i%64)) != 0 {
+				collide[i/64] |= 1 << (i % 64)
+			} else {
+				set[i/64] |= 1 << (i % 64)
+			}
+		}
+		for i := range set {
+			set[i] &^= collide[i]
+		}
+		// Keep colliding keys for the next level, in place.
+		next := remaining[:0]
+		for _, h := range remaining {
+			i := levelIndex(h, lvl, size)
+			if collide[i/64]&(1<<(i%64)) != 0 {
+				next = append(next, h)
+			}
+		}
+		remaining = next
+		bb.levels = append(bb.levels, level{offset: offset, size: size})
+	}
+	return len(remaining) == 0
+}
+
+func (bb *Hash) computeRanks() {
+	bb.ranks = bb.ranks[:0]
+	var rank uint64
+	for i, w := range bb.words {
+		if i%rankBlockWords == 0 {
+			bb.ranks = append(bb.ranks, rank)
+		}
+		rank += uint64(bits.OnesCount64(w))
+	}
+}
+
+// Hash returns the index of s in [0, Len()). Every key the hash was built with
+// gets a distinct index. For strings not in the key set Hash returns an arbitrary
+// index in [0, Len()), so the caller must compare against the key stored at the index.
+func (bb *Hash) Hash(s string) uint {
+	h := hash64(s, bb.seed)
+	for lvl, l := range bb.levels {
+		i := levelIndex(h, lvl, l.size)
+		pos := uint64(l.offset)*64 + i
+		if bb.words[pos/64]&(1<<(pos%64)) != 0 {
+			return uint(bb.rank(pos))
+		}
+	}
+	return 0
+}
+
+// Increment reports done since a BBHash is built rather than searched.
+// It is implemented so that a [Hash] satisfies [perfect.Hash] and can be
+// used with the root package's tooling, such as [perfect.NewLookup].
+func (bb *Hash) Increment() (done bool) { return true }
+
+// Len returns the number of keys the hash was built with.
+func (bb *Hash) Len() int { return bb.n }
+
+// Bits returns the total size of the hash's bit vectors and rank samples in bits.
+func (bb *Hash) Bits() int { return 64 * (len(bb.words) + len(bb.ranks)) }
+
+// BitsPerKey returns the space efficiency of the hash, [Hash.Bits] divided by [Hash.Len].
+func (bb *Hash) BitsPerKey() float64 { return float64(bb.Bits()) / float64(bb.n) }
+
+// rank returns the number of set bits before pos.
+func (bb *Hash) rank(pos uint64) uint64 {
+	word := pos / 64
+	block := word / rankBlockWords
+	rank := bb.ranks[block]
+	for _, w := range bb.words[block*rankBlockWords : word] {
+		rank += uint64(bits.OnesCount64(w))
+	}
+	return rank + uint64(bits.OnesCount64(bb.words[word]&(1<<(pos%64)-1)))
+}
+
+// MarshalBinary implements [encoding.BinaryMarshaler]. Rank samples are not serialized
+// and are recomputed by [Hash.UnmarshalBinary].
+func (bb *Hash) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, 4+8*(3+len(bb.levels)+len(bb.words)))
+	b = append(b, binaryMagic...)
+	b = binary.LittleEndian.AppendUint64(b, uint64(bb.n))
+	b = binary.LittleEndian.AppendUint64(b, bb.seed)
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(bb.levels)))
+	for _, l := range bb.levels {
+		b = binary.LittleEndian.AppendUint64(b, l.size)
+	}
+	for _, w := range bb.words {
+		b = binary.LittleEndian.AppendUint64(b, w)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary implements [encoding.BinaryUnmarshaler].
+func (bb *Hash) UnmarshalBinary(b []byte) error {
+	if len(b) < 4+3*8 || string(b[:4]) != binaryMagic {
+		return errors.New("bbhash: invalid header")
+	}
+	b = b[4:]
+	n := binary.LittleEndian.Uint64(b)
+	seed := binary.LittleEndian.Uint64(b[8:])
+	nlevels := binary.LittleEndian.Uint64(b[16:])
+	b = b[24:]
+	if n == 0 || nlevels == 0 || nlevels > maxLevels || uint64(len(b)) < 8*nlevels {
+		return errors.New("bbhash: invalid level count")
+	}
+	levels := make([]level, nlevels)
+	nwords := 0
+	for i := range levels {
+		size := binary.LittleEndian.Uint64(b)
+		b = b[8:]
+		if size == 0 || size%64 != 0 || size/64 > uint64(len(b))/8 {
+			return errors.New("bbhash: invalid level size")
+		}
+		levels[i] = level{offset: nwords, size: size}
+		nwords += int(size / 64)
+	}
+	// Levels are non-empty so nwords > 0 and the last word read below exists.
+	if len(b) != 8*nwords {
+		return errors.New("bbhash: bit vector length mismatch")
+	}
+	words := make([]uint64, nwords)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(b[8*i:])
+	}
+	*bb = Hash{n: int(n), seed: seed, levels: levels, words: words}
+	bb.computeRanks()
+	if bb.rank(uint64(64*nwords-1))+(words[nwords-1]>>63) != n {
+		*bb = Hash{}
+		return errors.New("bbhash: key count mismatch")
+	}
+	return nil
+}
+
+// levelIndex maps a key's hash onto a level's bit vector of size bits.
+func levelIndex(h uint64, lvl int, size uint64) uint64 {
+	h = mix(h + uint64(lvl)*0x9e3779b97f4a7c15)
+	hi, _ := bits.Mul64(h, size)
+	return hi
+}
+
+// hash64 hashes s eight bytes at a time.
+func hash64(s string, seed uint64) uint64 {
+	h := seed ^ uint64(len(s))*0x9e3779b97f4a7c15
+	for ; len(s) >= 8; s = s[8:] {
+		h = mix(h ^ binary.LittleEndian.Uint64([]byte(s[:8])))
+	}
+	if len(s) > 0 {
+		var tail [8]byte
+		copy(tail[:], s)
+		h = mix(h ^ binary.LittleEndian.Uint64(tail[:]))
+	}
+	return mix(h)
+}
+
+// mix is the splitmix64 finalizer.
+func mix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
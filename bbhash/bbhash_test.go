@@ -0,0 +1,191 @@
+package bbhash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestHash(t *testing.T) {
+	for _, n := range []int{1, 2, 63, 1000, 100_000} {
+		keys := randomKeys(n)
+		bb, err := New(keys, 2)
+		if err != nil {
+			t.Fatal(n, err)
+		}
+		testMinimalPerfect(t, bb, keys)
+	}
+}
+
+func TestMarshalBinary(t *testing.T) {
+	keys := randomKeys(5000)
+	bb, err := New(keys, 1.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := bb.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Hash
+	err = got.UnmarshalBinary(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, kw := range keys {
+		if got.Hash(kw) != bb.Hash(kw) {
+			t.Fatalf("unmarshalled hash differs for %q", kw)
+		}
+	}
+	testMinimalPerfect(t, &got, keys)
+	err = got.UnmarshalBinary(data[:len(data)-8])
+	if err == nil {
+		t.Fatal("expected error for truncated data")
+	}
+}
+
+func TestUnmarshalBinaryMalformed(t *testing.T) {
+	header := func(n, seed, nlevels uint64, rest ...uint64) []byte {
+		b := []byte(binaryMagic)
+		for _, v := range append([]uint64{n, seed, nlevels}, rest...) {
+			b = binary.LittleEndian.AppendUint64(b, v)
+		}
+		return b
+	}
+	for _, tc := range []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"bad magic", append([]byte("BBH0"), header(1, 0, 1, 64, 1)[4:]...)},
+		{"short header", header(1, 0, 1)[:20]},
+		{"zero keys", header(0, 0, 1, 64, 1)},
+		{"zero levels", header(1, 0, 0)},
+		{"too many levels", header(1, 0, maxLevels+1)},
+		{"missing level sizes", header(1, 0, 2, 64)},
+		{"zero level size", header(1, 0, 1, 0)},
+		{"unaligned level size", header(1, 0, 1, 65, 1, 0)},
+		{"missing words", header(1, 0, 1, 128, 1)},
+		{"trailing words", header(1, 0, 1, 64, 1, 0)},
+		{"key count mismatch", header(2, 0, 1, 64, 1)},
+	} {
+		var bb Hash
+		if err := bb.UnmarshalBinary(tc.data); err == nil {
+			t.Errorf("%s: expected error", tc.name)
+		}
+	}
+	var bb Hash
+	if err := bb.UnmarshalBinary(header(1, 0, 1, 64, 1)); err != nil {
+		t.Fatal("minimal hash:", err)
+	}
+	testMinimalPerfect(t, &bb, []string{"a"})
+}
+
+func FuzzUnmarshalBinary(f *testing.F) {
+	bb, err := New(randomKeys(100), 2)
+	if err != nil {
+		f.Fatal(err)
+	}
+	data, err := bb.MarshalBinary()
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(data)
+	f.Add([]byte(binaryMagic))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var bb Hash
+		if bb.UnmarshalBinary(data) != nil {
+			return
+		}
+		for _, kw := range []string{"", "a", "fuzz", "a longer key than eight bytes"} {
+			if h := bb.Hash(kw); h >= uint(bb.Len()) {
+				t.Fatalf("hash of %q out of range: %d", kw, h)
+			}
+		}
+		got, err := bb.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		} else if !bytes.Equal(got, data) {
+			t.Fatal("marshal does not round trip")
+		}
+	})
+}
+
+func TestDuplicateKeys(t *testing.T) {
+	_, err := New([]string{"a", "b", "a"}, 2)
+	if err == nil {
+		t.Fatal("expected error for duplicate keys")
+	}
+}
+
+func testMinimalPerfect(t *testing.T, bb *Hash, keys []string) {
+	t.Helper()
+	if bb.Len() != len(keys) {
+		t.Fatalf("want Len %d, got %d", len(keys), bb.Len())
+	}
+	seen := make([]bool, len(keys))
+	for _, kw := range keys {
+		h := bb.Hash(kw)
+		if h >= uint(len(keys)) {
+			t.Fatalf("hash of %q out of range: %d", kw, h)
+		} else if seen[h] {
+			t.Fatalf("hash of %q collides: %d", kw, h)
+		}
+		seen[h] = true
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	keys := randomKeys(100_000)
+	for _, gamma := range []float64{1, 1.5, 2, 3} {
+		b.Run(fmt.Sprintf("gamma=%v", gamma), func(b *testing.B) {
+			var bb *Hash
+			for b.Loop() {
+				var err error
+				bb, err = New(keys, gamma)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(bb.BitsPerKey(), "bits/key")
+		})
+	}
+}
+
+func BenchmarkHash(b *testing.B) {
+	keys := randomKeys(100_000)
+	for _, gamma := range []float64{1, 2, 3} {
+		bb, err := New(keys, gamma)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("gamma=%v", gamma), func(b *testing.B) {
+			var i int
+			for b.Loop() {
+				bb.Hash(keys[i%len(keys)])
+				i++
+			}
+			b.ReportMetric(bb.BitsPerKey(), "bits/key")
+		})
+	}
+}
+
+// randomKeys returns n unique identifier-like keys.
+func randomKeys(n int) []string {
+	rng := rand.New(rand.NewPCG(1, 1))
+	seen := make(map[string]bool, n)
+	keys := make([]string, 0, n)
+	for len(keys) < n {
+		b := make([]byte, 4+rng.IntN(20))
+		for i := range b {
+			b[i] = "abcdefghijklmnopqrstuvwxyz_ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"[rng.IntN(63)]
+		}
+		if !seen[string(b)] {
+			seen[string(b)] = true
+			keys = append(keys, string(b))
+		}
+	}
+	return keys
+}
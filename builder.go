@@ -0,0 +1,247 @@
+package perfect
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/bits"
+	"os"
+	"path/filepath"
+)
+
+// Builder builds a [Lookup] for key sets only known at run time, such as keywords read
+// from configuration at startup. It runs a bounded search over a few hash shapes and
+// table sizes and falls back to the near-perfect hash with the fewest collisions.
+// Found hashes are stored in Cache, if set, so that later builds of the same key set
+// skip the search.
+//
+// The sampled hashes searched rarely separate more than a few hundred keys. When none
+// of them is perfect a [HashSeeded] over the full key is searched as well, which leaves
+// a few percent of the keys colliding whatever their number. Colliding keys are resolved
+// by comparison so Build fails if more than MaxOverflow keys collide. Use [bbhash] for
+// key sets of more than a few hundred keys which the sampled hashes can't separate.
+//
+// [bbhash]: https://pkg.go.dev/github.com/soypat/perfect/bbhash
+type Builder struct {
+	// Cache stores found hashes by the [KeySetDigest] of their keys. Optional.
+	Cache Cache
+	// MaxAttempts bounds the hash configurations tried per hash shape and table size.
+	// Defaults to half a million key hashes worth of attempts, at least 16, so that
+	// builds of up to a few thousand keys take tens of milliseconds and larger
+	// builds take time proportional to the number of keys.
+	MaxAttempts int
+	// MaxOverflow bounds the keys of a built lookup which collide and are resolved
+	// by comparison on every lookup that misses their slot. Defaults to 16.
+	MaxOverflow int
+
+	finder HashFinder
+}
+
+// Cache stores serialized hashes by key set digest. Implementations should be safe for
+// concurrent use if shared between Builders used concurrently.
+type Cache interface {
+	// Get returns the data stored for digest and true, or false if there is none.
+	Get(digest string) (data []byte, ok bool)
+	// Put stores data for digest.
+	Put(digest string, data []byte) error
+}
+
+// DirCache is a [Cache] which stores each entry as a file named after its digest in the directory.
+type DirCache string
+
+// Get reads the entry of digest. Unreadable entries are treated as missing.
+func (dir DirCache) Get(digest string) ([]byte, bool) {
+	data, err := os.ReadFile(filepath.Join(string(dir), digest))
+	return data, err == nil
+}
+
+// Put writes the entry of digest, creating the directory if needed.
+func (dir DirCache) Put(digest string, data []byte) error {
+	err := os.MkdirAll(string(dir), 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(string(dir), digest), data, 0o644)
+}
+
+// KeySetDigest returns a hex encoded SHA-256 digest of keys. Order matters since
+// a [Lookup] maps keys to their index.
+func KeySetDigest(keys []string) string {
+	h := sha256.New()
+	var n [8]byte
+	for _, kw := range keys {
+		binary.LittleEndian.PutUint64(n[:], uint64(len(kw)))
+		h.Write(n[:])
+		h.Write([]byte(kw))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// builderEntry is the cached form of a built lookup. Exactly one of Hash and Seeded is set.
+type builderEntry struct {
+	TableBits int
+	Hash      *HashSequential `json:",omitempty"`
+	Seeded    *HashSeeded     `json:",omitempty"`
+}
+
+// hasher returns the hash of e, or false if e is not a valid entry for nkeys keys.
+func (e *builderEntry) hasher(nkeys int) (Hash, bool) {
+	minbits, maxbits := builderTableBits(nkeys)
+	if e.TableBits < minbits || e.TableBits > maxbits {
+		return nil, false
+	}
+	switch {
+	case e.Hash != nil && e.Seeded == nil:
+		return e.Hash, true
+	case e.Seeded != nil && e.Hash == nil && e.Seeded.Func != seededUndefined && e.Seeded.ConfigSeed() == nil:
+		return e.Seeded, true
+	}
+	return nil, false
+}
+
+// builderTableBits returns the range of table sizes searched by Builder for nkeys keys.
+func builderTableBits(nkeys int) (minbits, maxbits int) {
+	minbits = bits.Len(uint(nkeys)) + 1
+	return minbits, min(minbits+2, 32)
+}
+
+// builderShapes are the coefficient layouts tried by Builder, cheapest first.
+var builderShapes = [][]Coef{
+	{{IndexApplied: 0, Op: OpXor}, {IndexApplied: -1, Op: OpAdd}},
+	{{IndexApplied: 0, Op: OpXor}, {IndexApplied: 1, Op: OpAdd}, {IndexApplied: -1, Op: OpXor}},
+	{{IndexApplied: 0, Op: OpXor}, {IndexApplied: 1, Op: OpAdd}, {IndexApplied: -1, Op: OpXor}, {IndexApplied: -2, Op: OpAdd}},
+}
+
+// Build returns a lookup for keys, which must be unique. A cached hash is used if the
+// Cache has one for keys, otherwise hashes are searched with tables of 2 to 8 slots per key
+// and the result is stored in the Cache. Failing to store the result is not an error.
+// If the best hash found leaves more than MaxOverflow keys colliding Build returns
+// an error wrapping [ErrNoCoefficientsFound].
+func (b *Builder) Build(keys []string) (*Lookup, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("zero inputs")
+	}
+	seen := make(map[string]struct{}, len(keys))
+	for _, kw := range keys {
+		if _, dup := seen[kw]; dup {
+			return nil, errors.New("duplicate key " + kw)
+		}
+		seen[kw] = struct{}{}
+	}
+	var digest string
+	if b.Cache != nil {
+		digest = KeySetDigest(keys)
+		if data, ok := b.Cache.Get(digest); ok {
+			var entry builderEntry
+			if json.Unmarshal(data, &entry) == nil {
+				if hasher, ok := entry.hasher(len(keys)); ok {
+					l, err := NewLookup(hasher, entry.TableBits, keys)
+					if err == nil && len(l.overflow) <= b.maxOverflow() {
+						return l, nil
+					}
+				}
+			}
+			// Corrupt entry, search again and overwrite it.
+		}
+	}
+	entry, err := b.search(keys)
+	if err != nil {
+		return nil, err
+	}
+	hasher, _ := entry.hasher(len(keys))
+	l, err := NewLookup(hasher, entry.TableBits, keys)
+	if err != nil {
+		return nil, err
+	} else if len(l.overflow) > b.maxOverflow() {
+		return nil, fmt.Errorf("%d of %d keys collide, more than MaxOverflow %d: %w", len(l.overflow), len(keys), b.maxOverflow(), ErrNoCoefficientsFound)
+	}
+	if b.Cache != nil {
+		data, err := json.Marshal(entry)
+		if err == nil {
+			b.Cache.Put(digest, data)
+		}
+	}
+	return l, nil
+}
+
+// maxOverflow returns MaxOverflow or its default.
+func (b *Builder) maxOverflow() int {
+	if b.MaxOverflow <= 0 {
+		return 16
+	}
+	return b.MaxOverflow
+}
+
+// search looks for a perfect hash of keys over the builder's shapes and table sizes.
+// If there is none it also searches seeded hashes and returns the hash with the fewest collisions.
+func (b *Builder) search(keys []string) (builderEntry, error) {
+	maxAttempts := b.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = max(16, (1<<19)/len(keys))
+	}
+	const maxCoef = 16
+	minbits, maxbits := builderTableBits(len(keys))
+	if minbits > 32 {
+		return builderEntry{}, errors.New("too many keys")
+	}
+	var best builderEntry
+	bestCollisions := len(keys) + 1
+	for tblbits := minbits; tblbits <= maxbits; tblbits++ {
+		for _, shape := range builderShapes {
+			hasher := &attemptLimit{
+				HashSequential: &HashSequential{Coefs: append([]Coef{}, shape...)},
+				left:           maxAttempts,
+			}
+			err := hasher.ConfigCoefs(maxCoef)
+			if err != nil {
+				return builderEntry{}, err
+			}
+			result, err := b.finder.SearchBest(hasher, tblbits, keys)
+			if err != nil && !errors.Is(err, ErrNoCoefficientsFound) {
+				return builderEntry{}, err
+			}
+			if len(result.Collisions) < bestCollisions {
+				bestCollisions = len(result.Collisions)
+				best = builderEntry{TableBits: tblbits, Hash: result.Best.(*HashSequential)}
+			}
+			if bestCollisions == 0 {
+				return best, nil
+			}
+		}
+	}
+	// Sampled positions can not tell apart keys that only differ elsewhere.
+	// Hashing the full key leaves only the collisions expected of a random function.
+	for tblbits := minbits; tblbits <= maxbits; tblbits++ {
+		hasher := &HashSeeded{Func: SeededWyhash, MaxSeed: uint64(maxAttempts)}
+		err := hasher.ConfigSeed()
+		if err != nil {
+			return builderEntry{}, err
+		}
+		result, err := b.finder.SearchBest(hasher, tblbits, keys)
+		if err != nil && !errors.Is(err, ErrNoCoefficientsFound) {
+			return builderEntry{}, err
+		}
+		if len(result.Collisions) < bestCollisions {
+			bestCollisions = len(result.Collisions)
+			best = builderEntry{TableBits: tblbits, Seeded: result.Best.(*HashSeeded)}
+		}
+		if bestCollisions == 0 {
+			break
+		}
+	}
+	return best, nil
+}
+
+// attemptLimit exhausts the search of a HashSequential after a number of increments.
+type attemptLimit struct {
+	*HashSequential
+	left int
+}
+
+func (al *attemptLimit) Increment() (done bool) {
+	al.left--
+	return al.HashSequential.Increment() || al.left <= 0
+}
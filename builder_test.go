@@ -0,0 +1,88 @@
+package perfect
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"log"
+)
+
+// mapCache is a Cache which reports its use.
+type mapCache map[string][]byte
+
+func (c mapCache) Get(digest string) ([]byte, bool) {
+	data, ok := c[digest]
+	fmt.Println("cache hit:", ok)
+	return data, ok
+}
+
+func (c mapCache) Put(digest string, data []byte) error {
+	c[digest] = data
+	return nil
+}
+
+func ExampleBuilder() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	builder := Builder{Cache: mapCache{}}
+	for range 2 {
+		lookup, err := builder.Build(keywords)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		i, ok := lookup.Find("select")
+		fmt.Println(i, ok, keywords[i], lookup.Overflow())
+	}
+	// Output:
+	// cache hit: false
+	// 20 true select []
+	// cache hit: true
+	// 20 true select []
+}
+
+func ExampleBuilder_fullKey() {
+	// Keys which only differ in the middle can not be told apart by sampled positions.
+	// The full key hash leaves a few percent of them colliding, too many for large sets.
+	for _, n := range []int{200, 2000} {
+		var keys []string
+		for i := range n {
+			keys = append(keys, fmt.Sprintf("user_%04d_id", i))
+		}
+		var builder Builder
+		lookup, err := builder.Build(keys)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+		i, ok := lookup.Find("user_0042_id")
+		fmt.Println(i, ok, len(lookup.Overflow()), "of", len(keys), "keys overflow")
+	}
+	// Output:
+	// 42 true 2 of 200 keys overflow
+	// 97 of 2000 keys collide, more than MaxOverflow 16: no coefficients found
+}
+
+func ExampleBuilder_invalidCache() {
+	keys := []string{"alpha", "beta", "gamma"}
+	// An entry whose table is far too large for the keys is searched again and overwritten.
+	cache := mapCache{KeySetDigest(keys): []byte(`{"TableBits":32,"Hash":"h := uint(len(s))*1"}`)}
+	builder := Builder{Cache: cache}
+	lookup, err := builder.Build(keys)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var entry builderEntry
+	err = json.Unmarshal(cache[KeySetDigest(keys)], &entry)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	i, ok := lookup.Find("gamma")
+	fmt.Println(i, ok, entry.TableBits)
+	// Output:
+	// cache hit: true
+	// 2 true 3
+}
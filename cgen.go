@@ -0,0 +1,194 @@
+package perfect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CHasher is a [Hash] which can write itself as C source code.
+type CHasher interface {
+	Hash
+	// WriteCFunc writes the definition of a C99 function
+	// `static inline T name(const char *s, size_t len)` which returns the same value as Hash.
+	// T is an unsigned integer type, uintptr_t for hashes which depend on the width of uint,
+	// in which case the results agree when uintptr_t is as wide as the Go uint of the platform.
+	WriteCFunc(w io.Writer, name string) error
+}
+
+const cGenHeader = "/* Code generated by github.com/soypat/perfect. DO NOT EDIT. */\n\n"
+
+// WriteCFunc writes hs as a C function named name. See [CHasher].
+func (hs *HashSequential) WriteCFunc(w io.Writer, name string) error {
+	typ := "uintptr_t"
+	if hs.Width != WidthUint {
+		typ = hs.Width.String() + "_t"
+	}
+	fmt.Fprintf(w, "static inline %s %s(const char *s, size_t len) {\n", typ, name)
+	fmt.Fprintf(w, "\t%s h = (%s)len * %s;\n", typ, typ, cUint(hs.Width.wrap(uint64(hs.LenCoef.Value))))
+	for _, c := range hs.Coefs {
+		idx := c.IndexApplied
+		var cond, at string
+		if idx < 0 {
+			cond, at = fmt.Sprintf("len >= %d", -idx), fmt.Sprintf("len - %d", -idx)
+		} else {
+			cond, at = fmt.Sprintf("len > %d", idx), strconv.Itoa(idx)
+		}
+		fmt.Fprintf(w, "\tif (%s) h %s= (%s)(unsigned char)s[%s] * %s;\n", cond, c.Op, typ, at, cUint(hs.Width.wrap(uint64(c.Value))))
+	}
+	_, err := io.WriteString(w, "\treturn h;\n}\n")
+	return err
+}
+
+// WriteC writes a self-contained C99 header to w declaring a function
+// `static inline int name(const char *s, size_t len)` which returns the index of
+// the len bytes at s in the keys the lookup was built with, or -1 if they are not a key.
+// Tables are static const and the hash is the inline function name_hash.
+// The lookup's hash must implement [CHasher]. name must be an ASCII identifier.
+func (l *Lookup) WriteC(w io.Writer, name string) error {
+	if !isCIdentifier(name) {
+		return errors.New("invalid generated C function name " + strconv.Quote(name))
+	}
+	hasher, ok := l.hasher.(CHasher)
+	if !ok {
+		return fmt.Errorf("hash %T can not generate C code", l.hasher)
+	}
+	guard := strings.ToUpper(name) + "_H"
+	var buf bytes.Buffer
+	buf.WriteString(cGenHeader)
+	fmt.Fprintf(&buf, "#ifndef %s\n#define %s\n\n", guard, guard)
+	buf.WriteString("#include <stddef.h>\n#include <stdint.h>\n#include <string.h>\n\n")
+	err := hasher.WriteCFunc(&buf, name+"_hash")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(&buf, "\nstatic const char *const %s_keys[%d] = {\n", name, len(l.keys))
+	for _, kw := range l.keys {
+		fmt.Fprintf(&buf, "\t%s,\n", cQuote(kw))
+	}
+	fmt.Fprintf(&buf, "};\n\nstatic const size_t %s_key_lens[%d] = {", name, len(l.keys))
+	for i, kw := range l.keys {
+		if i%16 == 0 {
+			buf.WriteString("\n\t")
+		} else {
+			buf.WriteString(" ")
+		}
+		fmt.Fprintf(&buf, "%d,", len(kw))
+	}
+	fmt.Fprintf(&buf, "\n};\n\nstatic const %s_t %s_slots[%d] = {", goUintType(uint64(len(l.keys))), name, len(l.slots))
+	for i, slot := range l.slots {
+		if i%16 == 0 {
+			buf.WriteString("\n\t")
+		} else {
+			buf.WriteString(" ")
+		}
+		fmt.Fprintf(&buf, "%d,", slot)
+	}
+	buf.WriteString("\n};\n\n")
+	fmt.Fprintf(&buf, "/* %s returns the index of the len bytes at s in %s_keys or -1 if they are not a key. */\n", name, name)
+	fmt.Fprintf(&buf, "static inline int %s(const char *s, size_t len) {\n", name)
+	fmt.Fprintf(&buf, "\tsize_t i = %s_slots[%s_hash(s, len) & %du];\n", name, name, l.mask)
+	fmt.Fprintf(&buf, "\tif (i != 0 && %s_key_lens[i - 1] == len && memcmp(%s_keys[i - 1], s, len) == 0) return (int)(i - 1);\n", name, name)
+	for _, i := range l.overflow {
+		kw := l.keys[i]
+		fmt.Fprintf(&buf, "\tif (len == %d && memcmp(%s, s, len) == 0) return %d;\n", len(kw), cQuote(kw), i)
+	}
+	fmt.Fprintf(&buf, "\treturn -1;\n}\n\n#endif /* %s */\n", guard)
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// WriteGoCTest writes a Go test file to w checking that the C header written by
+// [Lookup.WriteC] with cfg.Name and the Go code written by [Lookup.WriteGo] with cfg agree on
+// the hash and index of every key and of near-miss non-keys. header is the path of the
+// C header relative to the generated package's directory. The test compiles a program
+// with the C compiler in the CC environment variable, or cc, and is skipped if there is none.
+func (l *Lookup) WriteGoCTest(w io.Writer, cfg GoConfig, header string) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	} else if !isCIdentifier(cfg.Name) {
+		return errors.New("invalid generated C function name " + strconv.Quote(cfg.Name))
+	}
+	name := cfg.Name
+	inputs := append(append([]string{}, l.keys...), nearMisses(l.keys)...)
+	var harness bytes.Buffer
+	fmt.Fprintf(&harness, "#include <stdio.h>\n#include %s\n\n", cQuote(header))
+	harness.WriteString("static const struct { const char *s; size_t len; } inputs[] = {\n")
+	for _, s := range inputs {
+		fmt.Fprintf(&harness, "\t{%s, %d},\n", cQuote(s), len(s))
+	}
+	harness.WriteString("};\n\nint main(void) {\n")
+	harness.WriteString("\tfor (size_t i = 0; i < sizeof(inputs) / sizeof(inputs[0]); i++) {\n")
+	fmt.Fprintf(&harness, "\t\tprintf(\"%%llu %%d\\n\", (unsigned long long)%s_hash(inputs[i].s, inputs[i].len), %s(inputs[i].s, inputs[i].len));\n", name, name)
+	harness.WriteString("\t}\n\treturn 0;\n}\n")
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, genHeader+"package %s\n\n", cfg.Package)
+	buf.WriteString("import (\n\"fmt\"\n\"os\"\n\"os/exec\"\n\"path/filepath\"\n\"strings\"\n\"testing\"\n)\n\n")
+	fmt.Fprintf(&buf, "func Test%sC(t *testing.T) {\n", exportedName(name))
+	buf.WriteString("cc := os.Getenv(\"CC\")\nif cc == \"\" {\ncc = \"cc\"\n}\n")
+	buf.WriteString("if _, err := exec.LookPath(cc); err != nil {\nt.Skip(\"no C compiler:\", err)\n}\n")
+	buf.WriteString("wd, err := os.Getwd()\nif err != nil {\nt.Fatal(err)\n}\n")
+	buf.WriteString("dir := t.TempDir()\nsrc := filepath.Join(dir, \"main.c\")\n")
+	fmt.Fprintf(&buf, "err = os.WriteFile(src, []byte(%sCHarness), 0o644)\nif err != nil {\nt.Fatal(err)\n}\n", name)
+	buf.WriteString("bin := filepath.Join(dir, \"main\")\n")
+	buf.WriteString("out, err := exec.Command(cc, \"-std=c99\", \"-Wall\", \"-I\", wd, \"-o\", bin, src).CombinedOutput()\n")
+	buf.WriteString("if err != nil {\nt.Fatalf(\"compiling C: %v\\n%s\", err, out)\n}\n")
+	buf.WriteString("out, err = exec.Command(bin).Output()\nif err != nil {\nt.Fatal(err)\n}\n")
+	buf.WriteString("lines := strings.Split(strings.TrimSpace(string(out)), \"\\n\")\n")
+	fmt.Fprintf(&buf, "if len(lines) != len(%sCInputs) {\n", name)
+	fmt.Fprintf(&buf, "t.Fatalf(\"got %%d C results, want %%d\", len(lines), len(%sCInputs))\n}\n", name)
+	fmt.Fprintf(&buf, "for i, s := range %sCInputs {\n", name)
+	fmt.Fprintf(&buf, "want := fmt.Sprintf(\"%%d %%d\", uint64(%sHash(s)), %s(s))\n", name, name)
+	buf.WriteString("if lines[i] != want {\nt.Errorf(\"%q: C hash and index %q, Go %q\", s, lines[i], want)\n}\n}\n}\n\n")
+	fmt.Fprintf(&buf, "var %sCInputs = [...]string{\n", name)
+	for _, s := range inputs {
+		fmt.Fprintf(&buf, "%q,\n", s)
+	}
+	buf.WriteString("}\n\n")
+	fmt.Fprintf(&buf, "const %sCHarness = %q\n", name, harness.String())
+	return writeGoSource(w, buf.Bytes())
+}
+
+// cQuote returns s as a C string literal. Bytes other than printable ASCII are octal escaped
+// and question marks are escaped to avoid trigraphs.
+func cQuote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"' || c == '\\' || c == '?':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c >= ' ' && c <= '~':
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "\\%03o", c)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// cUint returns v as a C unsigned integer constant.
+func cUint(v uint64) string {
+	if v > 0xffff_ffff {
+		return strconv.FormatUint(v, 10) + "ull"
+	}
+	return strconv.FormatUint(v, 10) + "u"
+}
+
+// isCIdentifier reports whether name is a valid identifier in both Go and C.
+func isCIdentifier(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if name[i] >= 0x80 {
+			return false
+		}
+	}
+	return token.IsIdentifier(name)
+}
@@ -0,0 +1,77 @@
+package perfect
+
+import (
+	"log"
+	"os"
+)
+
+func ExampleLookup_WriteC() {
+	keywords := []string{"if", "else", "for", "return", "func", "var", "const"}
+	hasher := &HashSequential{
+		Coefs: []Coef{
+			{IndexApplied: 0, Op: OpXor},
+			{IndexApplied: 1, Op: OpAdd},
+		},
+	}
+	err := hasher.ConfigCoefs(16)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	const tablesizebits = 4
+	_, err = phf.Search(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	lookup, err := NewLookup(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	err = lookup.WriteC(os.Stdout, "keyword")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// /* Code generated by github.com/soypat/perfect. DO NOT EDIT. */
+	//
+	// #ifndef KEYWORD_H
+	// #define KEYWORD_H
+	//
+	// #include <stddef.h>
+	// #include <stdint.h>
+	// #include <string.h>
+	//
+	// static inline uintptr_t keyword_hash(const char *s, size_t len) {
+	// 	uintptr_t h = (uintptr_t)len * 1u;
+	// 	if (len > 0) h ^= (uintptr_t)(unsigned char)s[0] * 1u;
+	// 	if (len > 1) h += (uintptr_t)(unsigned char)s[1] * 1u;
+	// 	return h;
+	// }
+	//
+	// static const char *const keyword_keys[7] = {
+	// 	"if",
+	// 	"else",
+	// 	"for",
+	// 	"return",
+	// 	"func",
+	// 	"var",
+	// 	"const",
+	// };
+	//
+	// static const size_t keyword_key_lens[7] = {
+	// 	2, 4, 3, 6, 4, 3, 5,
+	// };
+	//
+	// static const uint8_t keyword_slots[16] = {
+	// 	0, 1, 0, 0, 3, 7, 6, 5, 0, 4, 0, 0, 0, 2, 0, 0,
+	// };
+	//
+	// /* keyword returns the index of the len bytes at s in keyword_keys or -1 if they are not a key. */
+	// static inline int keyword(const char *s, size_t len) {
+	// 	size_t i = keyword_slots[keyword_hash(s, len) & 15u];
+	// 	if (i != 0 && keyword_key_lens[i - 1] == len && memcmp(keyword_keys[i - 1], s, len) == 0) return (int)(i - 1);
+	// 	return -1;
+	// }
+	//
+	// #endif /* KEYWORD_H */
+}
@@ -0,0 +1,230 @@
+package perfect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// OrderPreserving is an order-preserving minimal perfect hash built with the
+// Czech-Havas-Majewski (CHM) random graph method. Each key is mapped to a
+// user-specified value, so the hash of a key is its value and no indirection table is needed.
+//
+// H1 and H2 map keys to the vertices of a graph where each key is an edge. Once an
+// acyclic graph is found the vertices are assigned numbers g so that for every key
+//
+//	value = (g[H1(key) % m] + g[H2(key) % m]) % (maxValue+1)
+type OrderPreserving struct {
+	// H1 is the first vertex hash. It is left unchanged by the search.
+	H1 Hash
+	// H2 is the second vertex hash. It is incremented by [HashFinder.SearchOrderPreserving]
+	// until the key graph is acyclic, so its search space bounds the search.
+	H2 Hash
+
+	keys   []string // Keys indexed by value, empty string for unused values.
+	g      []uint   // Vertex numbers.
+	modulo uint     // Maximum value plus one.
+}
+
+// chmVertexRatio is the number of graph vertices per key. A random graph with
+// more than twice as many vertices as edges is acyclic with high probability.
+const chmVertexRatio = 2.09
+
+// chmValuesPerKey and chmMinValues bound the values of an [OrderPreserving], which holds
+// a key per value: values must be less than the larger of chmValuesPerKey per key and chmMinValues.
+const (
+	chmValuesPerKey = 64
+	chmMinValues    = 1 << 16
+)
+
+// SearchOrderPreserving searches an order-preserving hash mapping inputs[i] to values[i].
+// Inputs must be unique and non-empty and values must be unique and less than
+// the larger of 64 times the number of inputs and 65536. Returns the number of graphs tried.
+func (phf *HashFinder) SearchOrderPreserving(op *OrderPreserving, inputs []string, values []uint) (int, error) {
+	if len(inputs) == 0 {
+		return 0, errors.New("zero inputs")
+	} else if len(inputs) != len(values) {
+		return 0, errors.New("number of inputs and values differ")
+	} else if op.H1 == nil || op.H2 == nil {
+		return 0, errors.New("nil vertex hash")
+	} else if len(inputs) > math.MaxInt32/4 {
+		return 0, errors.New("too many inputs")
+	}
+	modulo := slices.Max(values) + 1
+	if modulo == 0 || modulo > math.MaxInt32 {
+		return 0, errors.New("maximum value too large")
+	} else if uint64(modulo) > max(chmValuesPerKey*uint64(len(inputs)), chmMinValues) {
+		return 0, errors.New("maximum value too large for the number of inputs")
+	}
+	keys := make([]string, modulo)
+	seen := make(map[string]struct{}, len(inputs))
+	for i, v := range values {
+		kw := inputs[i]
+		if keys[v] != "" {
+			return 0, fmt.Errorf("duplicate value %d", v)
+		} else if kw == "" {
+			return 0, errors.New("empty key") // Empty string marks unused values.
+		} else if _, dup := seen[kw]; dup {
+			return 0, errors.New("duplicate key " + kw)
+		}
+		seen[kw] = struct{}{}
+		keys[v] = kw
+	}
+	var g chmGraph
+	nvert := int(math.Ceil(chmVertexRatio*float64(len(inputs)))) + 1
+	attempts := 0
+	for {
+		attempts++
+		if g.build(op.H1, op.H2, nvert, inputs) && g.assign(values, modulo) {
+			break
+		}
+		if op.H2.Increment() {
+			return attempts, ErrNoCoefficientsFound
+		}
+	}
+	op.keys = keys
+	op.g = g.g
+	op.modulo = modulo
+	return attempts, nil
+}
+
+// Hash returns the value of s. Strings which are not keys return an arbitrary value
+// no greater than the maximum value. Must be called after a successful [HashFinder.SearchOrderPreserving].
+func (op *OrderPreserving) Hash(s string) uint {
+	m := uint(len(op.g))
+	return (op.g[op.H1.Hash(s)%m] + op.g[op.H2.Hash(s)%m]) % op.modulo
+}
+
+// Find returns the value of s. If s is not a key then ok is false.
+func (op *OrderPreserving) Find(s string) (value uint, ok bool) {
+	v := op.Hash(s)
+	if op.keys[v] != s {
+		return 0, false
+	}
+	return v, true
+}
+
+// WriteGo writes a Go source file to w declaring a function `func Name(s string) (Type, bool)`
+// which returns the value of s, or false if s is not a key. H1 and H2 must implement [GoHasher].
+func (op *OrderPreserving) WriteGo(w io.Writer, cfg GoConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	} else if len(op.g) == 0 {
+		return errors.New("order preserving hash not searched")
+	}
+	h1, ok1 := op.H1.(GoHasher)
+	h2, ok2 := op.H2.(GoHasher)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("hashes %T and %T must both generate Go code", op.H1, op.H2)
+	}
+	name, typ := cfg.Name, cfg.resultType()
+	var buf bytes.Buffer
+	writeGoHeader(&buf, cfg.Package, h1, h2)
+	fmt.Fprintf(&buf, "// %s returns the value of s, or false if s is not a key.\n", name)
+	fmt.Fprintf(&buf, "func %s(s string) (%s, bool) {\n", name, typ)
+	fmt.Fprintf(&buf, "v := (uint(%sG[%sH1(s)%%%d]) + uint(%sG[%sH2(s)%%%d])) %% %d\n", name, name, len(op.g), name, name, len(op.g), op.modulo)
+	fmt.Fprintf(&buf, "if %sKeys[v] != s {\nreturn 0, false\n}\n", name)
+	fmt.Fprintf(&buf, "return %s(v), true\n}\n\n", typ)
+
+	fmt.Fprintf(&buf, "var %sKeys = [%d]string{\n", name, op.modulo)
+	for v, kw := range op.keys {
+		if kw != "" {
+			fmt.Fprintf(&buf, "%d: %q,\n", v, kw)
+		}
+	}
+	buf.WriteString("}\n\n")
+	fmt.Fprintf(&buf, "var %sG = [%d]%s{", name, len(op.g), goUintType(uint64(op.modulo-1)))
+	for i, g := range op.g {
+		if i%16 == 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "%d, ", g)
+	}
+	buf.WriteString("\n}\n\n")
+	err := h1.WriteGoFunc(&buf, name+"H1")
+	if err != nil {
+		return err
+	}
+	buf.WriteString("\n")
+	err = h2.WriteGoFunc(&buf, name+"H2")
+	if err != nil {
+		return err
+	}
+	return writeGoSource(w, buf.Bytes())
+}
+
+// chmGraph is the key graph of the CHM algorithm in compressed adjacency form.
+type chmGraph struct {
+	edges   [][2]int32 // Vertices of each edge, one edge per key.
+	start   []int32    // Start of each vertex's incident edges in adj.
+	adj     []int32    // Incident edge indices of vertices.
+	g       []uint
+	visited []bool
+	stack   []int32
+}
+
+// build computes the graph's edges. Returns false if a key maps to a self-loop.
+func (g *chmGraph) build(h1, h2 Hash, nvert int, inputs []string) bool {
+	g.edges = g.edges[:0]
+	g.start = append(g.start[:0], make([]int32, nvert+1)...)
+	for _, kw := range inputs {
+		u, v := int32(h1.Hash(kw)%uint(nvert)), int32(h2.Hash(kw)%uint(nvert))
+		if u == v {
+			return false
+		}
+		g.edges = append(g.edges, [2]int32{u, v})
+		g.start[u+1]++
+		g.start[v+1]++
+	}
+	for i := 1; i < len(g.start); i++ {
+		g.start[i] += g.start[i-1]
+	}
+	g.adj = append(g.adj[:0], make([]int32, 2*len(inputs))...)
+	fill := slices.Clone(g.start[:nvert])
+	for e, uv := range g.edges {
+		for _, v := range uv {
+			g.adj[fill[v]] = int32(e)
+			fill[v]++
+		}
+	}
+	return true
+}
+
+// assign numbers vertices so that each edge's vertex numbers add up to its
+// value modulo. Returns false if the graph has a cycle.
+func (g *chmGraph) assign(values []uint, modulo uint) bool {
+	nvert := len(g.start) - 1
+	g.g = append(g.g[:0], make([]uint, nvert)...)
+	g.visited = append(g.visited[:0], make([]bool, nvert)...)
+	for root := range int32(nvert) {
+		if g.visited[root] {
+			continue
+		}
+		g.visited[root] = true
+		// Stack holds pairs of vertex and the edge it was reached through.
+		g.stack = append(g.stack[:0], root, -1)
+		for len(g.stack) > 0 {
+			u, from := g.stack[len(g.stack)-2], g.stack[len(g.stack)-1]
+			g.stack = g.stack[:len(g.stack)-2]
+			for _, e := range g.adj[g.start[u]:g.start[u+1]] {
+				if e == from {
+					continue
+				}
+				v := g.edges[e][0]
+				if v == u {
+					v = g.edges[e][1]
+				}
+				if g.visited[v] {
+					return false // Cycle.
+				}
+				g.visited[v] = true
+				g.g[v] = (values[e] + modulo - g.g[u]) % modulo
+				g.stack = append(g.stack, v, e)
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package perfect
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+func ExampleHashFinder_SearchOrderPreserving() {
+	// Map keywords to fixed token values of a lexer.
+	keywords := []string{"if", "else", "for", "return", "func", "var", "const"}
+	tokens := []uint{10, 11, 12, 20, 30, 31, 32}
+	h1 := &HashSequential{Coefs: []Coef{{IndexApplied: 0}, {IndexApplied: -1, Op: OpXor}}}
+	h2 := &HashSequential{Coefs: []Coef{{IndexApplied: 1}, {IndexApplied: -2, Op: OpXor}}}
+	h1.ConfigCoefs(32)
+	h2.ConfigCoefs(32)
+	op := &OrderPreserving{H1: h1, H2: h2}
+	var phf HashFinder
+	attempts, err := phf.SearchOrderPreserving(op, keywords, tokens)
+	if err != nil {
+		log.Fatalln(err, "after", attempts, "attempts")
+	}
+	fmt.Println("else =", op.Hash("else"), "const =", op.Hash("const"))
+	err = op.WriteGo(os.Stdout, GoConfig{Package: "lexer", Name: "lookupKeyword", Type: "Token"})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// else = 11 const = 32
+	// // Code generated by github.com/soypat/perfect. DO NOT EDIT.
+	//
+	// package lexer
+	//
+	// // lookupKeyword returns the value of s, or false if s is not a key.
+	// func lookupKeyword(s string) (Token, bool) {
+	// 	v := (uint(lookupKeywordG[lookupKeywordH1(s)%16]) + uint(lookupKeywordG[lookupKeywordH2(s)%16])) % 33
+	// 	if lookupKeywordKeys[v] != s {
+	// 		return 0, false
+	// 	}
+	// 	return Token(v), true
+	// }
+	//
+	// var lookupKeywordKeys = [33]string{
+	// 	10: "if",
+	// 	11: "else",
+	// 	12: "for",
+	// 	20: "return",
+	// 	30: "func",
+	// 	31: "var",
+	// 	32: "const",
+	// }
+	//
+	// var lookupKeywordG = [16]uint8{
+	// 	0, 0, 0, 0, 0, 29, 11, 21, 0, 9, 0, 2, 11, 10, 0, 0,
+	// }
+	//
+	// func lookupKeywordH1(s string) uint {
+	// 	h := uint(len(s)) * 1
+	// 	if len(s) > 0 {
+	// 		h += uint(s[0]) * 1
+	// 	}
+	// 	if len(s) >= 1 {
+	// 		h ^= uint(s[len(s)-1]) * 1
+	// 	}
+	// 	return h
+	// }
+	//
+	// func lookupKeywordH2(s string) uint {
+	// 	h := uint(len(s)) * 1
+	// 	if len(s) > 1 {
+	// 		h += uint(s[1]) * 1
+	// 	}
+	// 	if len(s) >= 2 {
+	// 		h ^= uint(s[len(s)-2]) * 1
+	// 	}
+	// 	return h
+	// }
+}
+
+func ExampleHashFinder_SearchOrderPreserving_valueRange() {
+	// A key is kept per value so sparse values far above the number of keys are rejected.
+	h1 := &HashSequential{Coefs: []Coef{{IndexApplied: 0}}}
+	h2 := &HashSequential{Coefs: []Coef{{IndexApplied: -1}}}
+	h1.ConfigCoefs(32)
+	h2.ConfigCoefs(32)
+	op := &OrderPreserving{H1: h1, H2: h2}
+	var phf HashFinder
+	_, err := phf.SearchOrderPreserving(op, []string{"low", "high"}, []uint{1, 1 << 30})
+	fmt.Println(err)
+	// Output:
+	// maximum value too large for the number of inputs
+}
@@ -0,0 +1,150 @@
+package perfect
+
+import (
+	"errors"
+	"math/bits"
+	"slices"
+)
+
+// Weights of the cost model of [HashSequential.Cost], in rough instruction counts.
+const (
+	costGuard  = 2 // Length check guarding a byte access and the load itself.
+	costNegIdx = 1 // Computing len(s)-N for negative indices.
+	costShift  = 1 // Multiplying by a power of two other than one compiles to a shift.
+	costMul    = 3 // Multiplication latency.
+	costOp     = 1 // Add or XOR of the operand into the hash.
+)
+
+// Cost estimates the run time cost of the hash in rough instruction counts, the same
+// as its generated code with [HashSequential.WriteGoFunc] would take for keys long
+// enough to reach every coefficient. Multiplying by one is free and by a power of two
+// is a shift, so configurations with fewer coefficients, positive indices and power of
+// two values, such as those of [Coef.OnlyPow2], are cheaper. Costs are only meaningful
+// compared to one another, see [HashFinder.SearchCheapest].
+func (hs *HashSequential) Cost() int {
+	cost := valueCost(hs.Width.wrap(uint64(hs.LenCoef.Value)))
+	for _, c := range hs.Coefs {
+		cost += costGuard + valueCost(hs.Width.wrap(uint64(c.Value)))
+		if c.IndexApplied < 0 {
+			cost += costNegIdx
+		}
+		if c.Op == OpMul {
+			cost += costMul
+		} else {
+			cost += costOp
+		}
+	}
+	return cost
+}
+
+// valueCost is the cost of multiplying by v.
+func valueCost(v uint64) int {
+	switch {
+	case v == 1:
+		return 0
+	case bits.OnesCount64(v) == 1:
+		return costShift
+	default:
+		return costMul
+	}
+}
+
+// maxCheapestCoefs bounds the coefficients of hashes given to SearchCheapest,
+// which searches every subset of them.
+const maxCheapestCoefs = 8
+
+// SearchCheapest searches for the perfect hash of lowest [HashSequential.Cost] within budget
+// attempts. Unlike [HashFinder.Search] it keeps searching after the first perfect hash found.
+// Besides the configuration of hs it searches hashes with a subset of its coefficients and
+// with power of two values only, see [Coef.OnlyPow2], cheapest shapes first. Configurations
+// no cheaper than the best hash found count as attempts but are not hashed, and shapes which
+// can't be cheaper are skipped. A budget of zero or less searches every shape exhaustively.
+//
+// hs is not modified and may have up to 8 coefficients. Returns [ErrNoCoefficientsFound]
+// if no perfect hash was found within budget.
+func (phf *HashFinder) SearchCheapest(hs *HashSequential, tableSizeBits int, inputs []string, budget int) (SearchResult, error) {
+	if len(hs.Coefs) == 0 {
+		return SearchResult{}, errors.New("hash has no coefficients")
+	} else if len(hs.Coefs) > maxCheapestCoefs {
+		return SearchResult{}, errors.New("too many coefficients to search cheapest subset")
+	}
+	if hs.LenCoef.MaxValue == 0 {
+		return SearchResult{}, errors.New("length coefficient not configured, see ConfigCoefs")
+	}
+	for i := range hs.Coefs {
+		if hs.Coefs[i].MaxValue == 0 || !hs.Coefs[i].Op.valid() {
+			return SearchResult{}, errors.New("coefficient not configured, see ConfigCoefs")
+		}
+	}
+	type shape struct {
+		hs      *HashSequential
+		minCost int // Cost with every value one.
+	}
+	allPow2 := hs.LenCoef.OnlyPow2
+	for i := range hs.Coefs {
+		allPow2 = allPow2 && hs.Coefs[i].OnlyPow2
+	}
+	var shapes []shape
+	for subset := 1; subset < 1<<len(hs.Coefs); subset++ {
+		for _, pow2 := range []bool{true, false} {
+			if pow2 && allPow2 {
+				continue // Same as the shape as given.
+			}
+			s := &HashSequential{LenCoef: hs.LenCoef, Width: hs.Width}
+			for i, c := range hs.Coefs {
+				if subset&(1<<i) != 0 {
+					s.Coefs = append(s.Coefs, c)
+				}
+			}
+			if pow2 {
+				s.LenCoef.OnlyPow2 = true
+				for i := range s.Coefs {
+					s.Coefs[i].OnlyPow2 = true
+				}
+			}
+			s.LenCoef.init()
+			for i := range s.Coefs {
+				s.Coefs[i].init()
+			}
+			lowest := s.Clone().(*HashSequential)
+			lowest.LenCoef.Value = 1
+			for i := range lowest.Coefs {
+				lowest.Coefs[i].Value = 1
+			}
+			shapes = append(shapes, shape{hs: s, minCost: lowest.Cost()})
+		}
+	}
+	slices.SortStableFunc(shapes, func(a, b shape) int { return a.minCost - b.minCost })
+
+	mask, err := phf.init(tableSizeBits, inputs)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	m := newKeyMatrix(inputs, coefPositions(hs, 0))
+	var result SearchResult
+	bestCost := -1
+search:
+	for _, s := range shapes {
+		if bestCost >= 0 && s.minCost >= bestCost {
+			continue // Can't improve on best.
+		}
+		for {
+			if budget > 0 && result.Attempts >= budget {
+				break search
+			}
+			result.Attempts++
+			// Only hash configurations cheaper than the best found.
+			if cost := s.hs.Cost(); (bestCost < 0 || cost < bestCost) && phf.perfect(s.hs, m, mask) {
+				bestCost = cost
+				result.Best = s.hs.Clone()
+			}
+			if s.hs.Increment() {
+				break
+			}
+		}
+	}
+	if result.Best == nil {
+		return result, ErrNoCoefficientsFound
+	}
+	return result, nil
+}
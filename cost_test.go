@@ -0,0 +1,55 @@
+package perfect
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+)
+
+func ExampleHashFinder_SearchCheapest() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	newHasher := func() *HashSequential {
+		hasher := &HashSequential{
+			Coefs: []Coef{
+				{IndexApplied: 0, Op: OpXor},
+				{IndexApplied: 1, Op: OpAdd},
+				{IndexApplied: -1, Op: OpMul},
+				{IndexApplied: -2, Op: OpXor},
+			},
+		}
+		err := hasher.ConfigCoefs(32)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return hasher
+	}
+	var phf HashFinder
+	first := newHasher()
+	_, err := phf.Search(first, 7, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("first found, cost %d:\n%s", first.Cost(), first)
+	result, err := phf.SearchCheapest(newHasher(), 7, keywords, 1<<20)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	cheapest := result.Best.(*HashSequential)
+	fmt.Printf("cheapest found, cost %d:\n%s", cheapest.Cost(), cheapest)
+	// Output:
+	// first found, cost 19:
+	// h := uint(len(s))*1
+	// h ^= uint(s[0])*22
+	// h += uint(s[1])*1
+	// h *= uint(s[len(s)-1])*1
+	// h ^= uint(s[len(s)-2])*1
+	// cheapest found, cost 7:
+	// h := uint(len(s))*16
+	// h ^= uint(s[0])*1
+	// h += uint(s[1])*1
+}
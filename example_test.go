@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"go/token"
 	"log"
+	"os"
+	"testing"
 )
 
 func ExampleHashFinder_goKeywords() {
@@ -42,3 +44,181 @@ func ExampleHashFinder_goKeywords() {
 	// h ^= uint(s[0])*1
 	// h ^= uint(s[1])*8
 }
+
+func ExampleWidth() {
+	hasher := &HashSequential{
+		LenCoef: Coef{Value: 0x9e3779b9},
+		Coefs: []Coef{
+			{IndexApplied: 0, Op: OpMul, Value: 0x85ebca6b},
+			{IndexApplied: -1, Op: OpXor, Value: 77},
+		},
+		Width: Width32, // Same hash on 32 and 64-bit platforms.
+	}
+	fmt.Print(hasher.String())
+	fmt.Println(hasher.Hash("keyword"))
+	err := hasher.WriteGoFunc(os.Stdout, "keywordHash")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// h := uint32(len(s))*2654435769
+	// h *= uint32(s[0])*2246822507
+	// h ^= uint32(s[len(s)-1])*77
+	// 3631536835
+	// func keywordHash(s string) uint {
+	// 	h := uint32(len(s)) * 2654435769
+	// 	if len(s) > 0 {
+	// 		h *= uint32(s[0]) * 2246822507
+	// 	}
+	// 	if len(s) >= 1 {
+	// 		h ^= uint32(s[len(s)-1]) * 77
+	// 	}
+	// 	return uint(h)
+	// }
+}
+
+// BenchmarkSearch measures search attempts, in which the first collision ends the attempt,
+// across table sizes. Marking slots with attempt epochs is compared against clearing the
+// table before every attempt, whose cost grows with the table size.
+func BenchmarkSearch(b *testing.B) {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	const attemptsPerSearch = 64
+	for _, tableSizeBits := range []int{6, 10, 16, 20} {
+		for _, reset := range []string{"epoch", "clear"} {
+			b.Run(fmt.Sprintf("bits=%d/%s", tableSizeBits, reset), func(b *testing.B) {
+				hasher := &attemptLimit{HashSequential: &HashSequential{
+					Coefs: []Coef{{IndexApplied: 0, Op: OpXor}, {IndexApplied: 1, Op: OpXor}, {IndexApplied: -1, Op: OpAdd}},
+				}}
+				// Keep order so that only the table size, not the order learned by earlier
+				// attempts, changes between runs.
+				phf := HashFinder{KeepOrder: true}
+				table := make([]bool, 1<<tableSizeBits)
+				attempts := 0
+				for b.Loop() {
+					// Every search tries the same configurations, ending early on a perfect one.
+					err := hasher.ConfigCoefs(1 << 10)
+					if err != nil {
+						b.Fatal(err)
+					}
+					hasher.left = attemptsPerSearch
+					if reset == "clear" {
+						attempts += clearingSearch(hasher, table, keywords)
+					} else {
+						n, _ := phf.Search(hasher, tableSizeBits, keywords)
+						attempts += n
+					}
+				}
+				b.ReportMetric(float64(attempts)/b.Elapsed().Seconds(), "attempts/s")
+			})
+		}
+	}
+}
+
+// clearingSearch is the search loop of [HashFinder.Search] with the table cleared before
+// every attempt instead of marked with epochs. Returns the number of attempts.
+func clearingSearch(hasher Hash, table []bool, inputs []string) int {
+	mask := uint(len(table)) - 1
+	for attempts := 1; ; attempts++ {
+		clear(table)
+		perfect := true
+		for _, kw := range inputs {
+			h := hasher.Hash(kw) & mask
+			if table[h] {
+				perfect = false
+				break
+			}
+			table[h] = true
+		}
+		if perfect || hasher.Increment() {
+			return attempts
+		}
+	}
+}
+
+// hashOnly hides the concrete type of a hash so searches take their generic path.
+type hashOnly struct{ *HashSequential }
+
+// BenchmarkSearchMatrix compares exhaustive searches hashing a HashSequential from the bytes
+// extracted once per key against hashing the keys through the Hash interface.
+func BenchmarkSearchMatrix(b *testing.B) {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	for _, matrix := range []bool{true, false} {
+		name := "generic"
+		if matrix {
+			name = "matrix"
+		}
+		b.Run(name, func(b *testing.B) {
+			var phf HashFinder
+			attempts := 0
+			for b.Loop() {
+				// Too few slots for a perfect hash so the whole search space is tried.
+				hasher := &HashSequential{
+					Coefs: []Coef{{IndexApplied: 0, Op: OpXor}, {IndexApplied: 1, Op: OpXor}, {IndexApplied: -1, Op: OpAdd}},
+				}
+				err := hasher.ConfigCoefs(8)
+				if err != nil {
+					b.Fatal(err)
+				}
+				var n int
+				if matrix {
+					n, err = phf.Search(hasher, 5, keywords)
+				} else {
+					n, err = phf.Search(hashOnly{hasher}, 5, keywords)
+				}
+				if err != ErrNoCoefficientsFound {
+					b.Fatal("unexpected search result:", err)
+				}
+				attempts += n
+			}
+			b.ReportMetric(float64(attempts)/b.Elapsed().Seconds(), "attempts/s")
+		})
+	}
+}
+
+// BenchmarkSearchBest compares exhaustive searches hashing a HashSequential from the bytes
+// extracted once per key against hashing the keys through the Hash interface.
+func BenchmarkSearchBest(b *testing.B) {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	for _, matrix := range []bool{true, false} {
+		name := "generic"
+		if matrix {
+			name = "matrix"
+		}
+		b.Run(name, func(b *testing.B) {
+			var phf HashFinder
+			for b.Loop() {
+				// Too few slots for a perfect hash so the whole search space is tried.
+				hasher := &HashSequential{
+					Coefs: []Coef{{IndexApplied: 0, Op: OpXor}, {IndexApplied: 1, Op: OpXor}, {IndexApplied: -1, Op: OpAdd}, {IndexApplied: -2, Op: OpAdd}},
+				}
+				err := hasher.ConfigCoefs(6)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if matrix {
+					_, err = phf.SearchBest(hasher, 5, keywords)
+				} else {
+					_, err = phf.SearchBest(hashOnly{hasher}, 5, keywords)
+				}
+				if err != ErrNoCoefficientsFound {
+					b.Fatal("unexpected search result:", err)
+				}
+			}
+		})
+	}
+}
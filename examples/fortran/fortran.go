@@ -12,28 +12,7 @@ import (
 )
 
 func main() {
-	// Populate intrinsics, keywords.
-	var keywords, intrinsics, vendored []string
-	for kw := keywordBeg + 1; kw < keywordEnd; kw++ {
-		s := kw.String()
-		if strings.ToUpper(s) != s {
-			continue // is a separator token.
-		}
-		keywords = append(keywords, s)
-	}
-	for intr := intrinsicUndefined + 1; intr < fortran77End; intr++ {
-		s := intr.String()
-		if strings.ToUpper(s) != s {
-			continue // is a separator token.
-		}
-		intrinsics = append(intrinsics, s)
-	}
-	for vndr := vendorUndefined + 1; vndr < vendorPGIEnd; vndr++ {
-		if !vndr.IsValid() {
-			continue
-		}
-		vendored = append(vendored, vndr.String())
-	}
+	keywords, intrinsics, vendored := keySets()
 	const maxCoef = 64
 	hasher := &perfect.HashSequential{
 		LenCoef: perfect.Coef{MaxValue: maxCoef},
@@ -124,6 +103,101 @@ func main() {
 	} else {
 		log.Printf("vendored: no perfect hash found after %d attempts", attempts)
 	}
+
+	// ANNEALED VENDORED INTRINSICS.
+
+	// Local search scores configurations by collision count and also moves coefficient positions.
+	annealed := &perfect.HashSequential{
+		Coefs: []perfect.Coef{{IndexApplied: 0}, {IndexApplied: 1}, {IndexApplied: -2}, {IndexApplied: -1}},
+	}
+	annealed.ConfigCoefs(maxCoef)
+	log.Printf("annealing: Searching perfect hash for %d intrinsics(vendored) with %d coefficients", len(vendored), len(annealed.Coefs)+1)
+	tm = timer("annealed vendored intrinsic search")
+	result, err := phf.SearchAnneal(annealed, 10, vendored, perfect.Anneal{Temperature: 1, MaxValue: maxCoef, MaxIndex: 4, Seed: 1})
+	if err == nil {
+		tm()
+		log.Printf("annealing: perfect hash found after %d attempts:\n%s", result.Attempts, result.Best.(*perfect.HashSequential).String())
+	} else {
+		log.Printf("annealing: %d collisions left after %d attempts", len(result.Collisions), result.Attempts)
+	}
+
+	// LENGTH DISPATCHED KEYWORDS.
+
+	// Keywords such as END, ENDDO and ENDIF are mostly separable by length alone.
+	ld := &perfect.LengthDispatch{
+		Template: perfect.HashSequential{Coefs: []perfect.Coef{{IndexApplied: 0}, {IndexApplied: 1, Op: perfect.OpXor}, {IndexApplied: -1, Op: perfect.OpXor}, {IndexApplied: -2}}},
+	}
+	ld.Template.ConfigCoefs(8)
+	log.Printf("length dispatch: Searching per-length hashes for %d keywords", len(keywords))
+	tm = timer("length dispatch keyword search")
+	attempts, err = phf.SearchLengthDispatch(ld, keywords)
+	if err != nil {
+		log.Fatalf("length dispatch: no hash found after %d attempts: %s", attempts, err)
+	}
+	tm()
+	log.Printf("length dispatch: hashes found after %d attempts", attempts)
+
+	// ORDER PRESERVING KEYWORDS.
+
+	// Map keywords directly to their Token value so no indirection table is needed.
+	var keywordValues []uint
+	for _, kw := range keywords {
+		keywordValues = append(keywordValues, uint(tokenByName(kw)))
+	}
+	h1 := &perfect.HashSequential{Coefs: []perfect.Coef{{IndexApplied: 0}, {IndexApplied: 1, Op: perfect.OpXor}, {IndexApplied: -1, Op: perfect.OpMul}}}
+	h2 := &perfect.HashSequential{Coefs: []perfect.Coef{{IndexApplied: -2}, {IndexApplied: 2, Op: perfect.OpMul}, {IndexApplied: -1, Op: perfect.OpXor}}}
+	h1.ConfigCoefs(maxCoef)
+	h2.ConfigCoefs(maxCoef)
+	op := &perfect.OrderPreserving{H1: h1, H2: h2}
+	log.Printf("order preserving: Searching CHM hash for %d keywords", len(keywords))
+	tm = timer("order preserving keyword search")
+	attempts, err = phf.SearchOrderPreserving(op, keywords, keywordValues)
+	if err != nil {
+		log.Printf("order preserving: no hash found after %d attempts", attempts)
+		return
+	}
+	tm()
+	for _, kw := range keywords {
+		v, ok := op.Find(kw)
+		if !ok || Token(v).String() != kw {
+			log.Fatalf("order preserving: %s maps to %s", kw, Token(v))
+		}
+	}
+	log.Printf("order preserving: hash found after %d attempts, all keywords map to their Token", attempts)
+}
+
+// keySets returns the keywords, the Fortran 77 intrinsics and the vendored intrinsics.
+func keySets() (keywords, intrinsics, vendored []string) {
+	for kw := keywordBeg + 1; kw < keywordEnd; kw++ {
+		s := kw.String()
+		if strings.ToUpper(s) != s {
+			continue // is a separator token.
+		}
+		keywords = append(keywords, s)
+	}
+	for intr := intrinsicUndefined + 1; intr < fortran77End; intr++ {
+		s := intr.String()
+		if strings.ToUpper(s) != s {
+			continue // is a separator token.
+		}
+		intrinsics = append(intrinsics, s)
+	}
+	for vndr := vendorUndefined + 1; vndr < vendorPGIEnd; vndr++ {
+		if !vndr.IsValid() {
+			continue
+		}
+		vendored = append(vendored, vndr.String())
+	}
+	return keywords, intrinsics, vendored
+}
+
+func tokenByName(s string) Token {
+	for tok := keywordBeg + 1; tok < keywordEnd; tok++ {
+		if tok.String() == s {
+			return tok
+		}
+	}
+	return Undefined
 }
 
 func randomSearch(hasher *perfect.HashSequential, phf *perfect.HashFinder, words []string, tableBits int, randomRetries int) (attempts int, err error) {
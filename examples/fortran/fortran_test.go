@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/soypat/perfect"
+)
+
+// BenchmarkSearchOrder compares exhaustive searches hashing inputs in the given order
+// against adaptive ordering, which hashes keys likely to collide first.
+func BenchmarkSearchOrder(b *testing.B) {
+	keywords, intrinsics, vendored := keySets()
+	sets := []struct {
+		name string
+		keys []string
+	}{
+		{name: "keywords", keys: keywords},
+		{name: "intrinsics", keys: intrinsics},
+		{name: "vendored", keys: vendored},
+	}
+	for _, set := range sets {
+		for _, keepOrder := range []bool{true, false} {
+			name := set.name + "/adaptive"
+			if keepOrder {
+				name = set.name + "/given"
+			}
+			b.Run(name, func(b *testing.B) {
+				phf := perfect.HashFinder{KeepOrder: keepOrder}
+				attempts := 0
+				for b.Loop() {
+					// A search space too small for a perfect hash so every attempt fails.
+					hasher := &perfect.HashSequential{
+						Coefs: []perfect.Coef{{IndexApplied: 0}, {IndexApplied: 1, Op: perfect.OpXor}, {IndexApplied: -1}},
+					}
+					hasher.ConfigCoefs(8)
+					n, err := phf.Search(hasher, 10, set.keys)
+					if !errors.Is(err, perfect.ErrNoCoefficientsFound) {
+						b.Fatal("unexpected search result:", err)
+					}
+					attempts += n
+				}
+				b.ReportMetric(float64(attempts)/b.Elapsed().Seconds(), "attempts/s")
+			})
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package perfect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"slices"
+	"strconv"
+)
+
+// GoConfig configures generation of Go source code for a lookup.
+type GoConfig struct {
+	Package string // Package clause of the generated file.
+	// Name of the generated lookup function. Other generated declarations
+	// are prefixed with Name to avoid clashing with user code.
+	Name string
+	// Type is the result type of generated lookups which map keys to
+	// user-specified values, such as [OrderPreserving]. Defaults to int.
+	Type string
+}
+
+// GoHasher is a [Hash] which can write itself as Go source code.
+type GoHasher interface {
+	Hash
+	// WriteGoFunc writes the declaration of a function `func name(s string) uint`
+	// which returns the same value as Hash, along with any declarations it depends on.
+	WriteGoFunc(w io.Writer, name string) error
+}
+
+// goImporter is implemented by GoHashers whose generated functions use other packages.
+type goImporter interface {
+	// goImports returns the import paths of the packages used by WriteGoFunc.
+	goImports() []string
+}
+
+const genHeader = "// Code generated by github.com/soypat/perfect. DO NOT EDIT.\n\n"
+
+// writeGoHeader writes the generated file header, the package clause and
+// the imports of the functions generated by hashers to buf.
+func writeGoHeader(buf *bytes.Buffer, pkg string, hashers ...GoHasher) {
+	fmt.Fprintf(buf, genHeader+"package %s\n\n", pkg)
+	var imports []string
+	for _, h := range hashers {
+		if gi, ok := h.(goImporter); ok {
+			imports = append(imports, gi.goImports()...)
+		}
+	}
+	slices.Sort(imports)
+	for _, path := range slices.Compact(imports) {
+		fmt.Fprintf(buf, "import %q\n\n", path)
+	}
+}
+
+func (cfg GoConfig) validate() error {
+	if !token.IsIdentifier(cfg.Package) {
+		return errors.New("invalid generated package name " + strconv.Quote(cfg.Package))
+	} else if !token.IsIdentifier(cfg.Name) {
+		return errors.New("invalid generated function name " + strconv.Quote(cfg.Name))
+	} else if cfg.Type != "" && !token.IsIdentifier(cfg.Type) {
+		return errors.New("invalid generated result type " + strconv.Quote(cfg.Type))
+	}
+	return nil
+}
+
+func (cfg GoConfig) resultType() string {
+	if cfg.Type == "" {
+		return "int"
+	}
+	return cfg.Type
+}
+
+// WriteGoFunc writes hs as a Go function named name. See [GoHasher].
+func (hs *HashSequential) WriteGoFunc(w io.Writer, name string) error {
+	var buf bytes.Buffer
+	typ := hs.Width.String()
+	fmt.Fprintf(&buf, "func %s(s string) uint {\n", name)
+	fmt.Fprintf(&buf, "h := %s(len(s)) * %d\n", typ, hs.Width.wrap(uint64(hs.LenCoef.Value)))
+	for _, c := range hs.Coefs {
+		writeGoCoef(&buf, c, typ, strconv.FormatUint(hs.Width.wrap(uint64(c.Value)), 10))
+	}
+	if hs.Width == WidthUint {
+		buf.WriteString("return h\n}\n")
+	} else {
+		buf.WriteString("return uint(h)\n}\n")
+	}
+	return writeGoSource(w, buf.Bytes())
+}
+
+// writeGoCoef writes the Go statement applying c to h of type typ with value expression v,
+// guarding the index access the same way [Coef.Apply] does.
+func writeGoCoef(buf *bytes.Buffer, c Coef, typ, v string) {
+	idx := c.IndexApplied
+	if idx < 0 {
+		fmt.Fprintf(buf, "if len(s) >= %d {\nh %s= %s(s[len(s)%d]) * %s\n}\n", -idx, c.Op, typ, idx, v)
+	} else {
+		fmt.Fprintf(buf, "if len(s) > %d {\nh %s= %s(s[%d]) * %s\n}\n", idx, c.Op, typ, idx, v)
+	}
+}
+
+// WriteGo writes a Go source file to w declaring a function `func Name(s string) int`
+// which returns the index of s in the keys the lookup was built with, or -1 if s is not a key.
+// The lookup's hash must implement [GoHasher].
+func (l *Lookup) WriteGo(w io.Writer, cfg GoConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	hasher, ok := l.hasher.(GoHasher)
+	if !ok {
+		return fmt.Errorf("hash %T can not generate Go code", l.hasher)
+	}
+	name := cfg.Name
+	var buf bytes.Buffer
+	writeGoHeader(&buf, cfg.Package, hasher)
+	fmt.Fprintf(&buf, "// %s returns the index of s in %sKeys or -1 if s is not a key.\n", name, name)
+	fmt.Fprintf(&buf, "func %s(s string) int {\n", name)
+	fmt.Fprintf(&buf, "i := %sSlots[%sHash(s)&%d]\n", name, name, l.mask)
+	fmt.Fprintf(&buf, "if i != 0 && %sKeys[i-1] == s {\nreturn int(i - 1)\n}\n", name)
+	if len(l.overflow) > 0 {
+		buf.WriteString("switch s {\n")
+		for _, i := range l.overflow {
+			fmt.Fprintf(&buf, "case %q:\nreturn %d\n", l.keys[i], i)
+		}
+		buf.WriteString("}\n")
+	}
+	buf.WriteString("return -1\n}\n\n")
+	writeGoKeys(&buf, name+"Keys", l.keys)
+	writeGoSlots(&buf, name+"Slots", l.slots, len(l.keys))
+	err := hasher.WriteGoFunc(&buf, name+"Hash")
+	if err != nil {
+		return err
+	}
+	return writeGoSource(w, buf.Bytes())
+}
+
+// WriteGoTest writes a Go test file to w for the code generated by [Lookup.WriteGo] with the same cfg.
+// The file contains a table test checking every key maps to its index and slot, a test
+// checking near-miss non-keys are rejected, a fuzz test comparing against a map,
+// and a benchmark comparing the lookup against a map[string]int. Its declarations clash
+// with those of [WriteGoBenchmark] for the same cfg.Name.
+func (l *Lookup) WriteGoTest(w io.Writer, cfg GoConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	name := cfg.Name
+	exported := exportedName(name)
+	slotOf := l.keySlots()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, genHeader+"package %s\n\nimport \"testing\"\n\n", cfg.Package)
+
+	fmt.Fprintf(&buf, "func Test%s(t *testing.T) {\n", exported)
+	fmt.Fprintf(&buf, "for _, test := range %sTests {\n", name)
+	fmt.Fprintf(&buf, "got := %s(test.key)\nif got != test.index {\n", name)
+	fmt.Fprintf(&buf, "t.Errorf(\"%s(%%q) = %%d, want %%d\", test.key, got, test.index)\ncontinue\n}\n", name)
+	fmt.Fprintf(&buf, "if %sKeys[got] != test.key {\n", name)
+	fmt.Fprintf(&buf, "t.Errorf(\"%sKeys[%%d] = %%q, want %%q\", got, %sKeys[got], test.key)\n}\n", name, name)
+	buf.WriteString("if test.slot < 0 {\ncontinue // Resolved by comparison.\n}\n")
+	fmt.Fprintf(&buf, "if slot := int(%sHash(test.key) & %d); slot != test.slot {\n", name, l.mask)
+	buf.WriteString("t.Errorf(\"%q hashed to slot %d, want %d\", test.key, slot, test.slot)\n}\n")
+	fmt.Fprintf(&buf, "if i := int(%sSlots[test.slot]) - 1; i != test.index {\n", name)
+	buf.WriteString("t.Errorf(\"slot %d of %q holds index %d, want %d\", test.slot, test.key, i, test.index)\n}\n}\n}\n\n")
+
+	fmt.Fprintf(&buf, "func Test%sNearMiss(t *testing.T) {\n", exported)
+	fmt.Fprintf(&buf, "for _, s := range %sNearMisses {\n", name)
+	fmt.Fprintf(&buf, "if got := %s(s); got != -1 {\n", name)
+	fmt.Fprintf(&buf, "t.Errorf(\"%s(%%q) = %%d, want -1\", s, got)\n}\n}\n}\n\n", name)
+
+	fmt.Fprintf(&buf, "func Fuzz%s(f *testing.F) {\n", exported)
+	fmt.Fprintf(&buf, "want := make(map[string]int, len(%sKeys))\n", name)
+	fmt.Fprintf(&buf, "for i, kw := range %sKeys {\nwant[kw] = i\nf.Add(kw)\n}\n", name)
+	fmt.Fprintf(&buf, "for _, s := range %sNearMisses {\nf.Add(s)\n}\n", name)
+	buf.WriteString("f.Fuzz(func(t *testing.T, s string) {\n")
+	buf.WriteString("i, ok := want[s]\nif !ok {\ni = -1\n}\n")
+	fmt.Fprintf(&buf, "if got := %s(s); got != i {\n", name)
+	fmt.Fprintf(&buf, "t.Errorf(\"%s(%%q) = %%d, want %%d\", s, got, i)\n}\n})\n}\n\n", name)
+
+	fmt.Fprintf(&buf, "func Benchmark%s(b *testing.B) {\n", exported)
+	fmt.Fprintf(&buf, "inputs := %sBenchInputs[:]\n", name)
+	fmt.Fprintf(&buf, "b.Run(\"lookup\", func(b *testing.B) {\nfor i := 0; i < b.N; i++ {\n%s(inputs[i%%len(inputs)])\n}\n})\n", name)
+	fmt.Fprintf(&buf, "m := make(map[string]int, len(%sKeys))\n", name)
+	fmt.Fprintf(&buf, "for i, kw := range %sKeys {\nm[kw] = i\n}\n", name)
+	buf.WriteString("b.Run(\"map\", func(b *testing.B) {\nfor i := 0; i < b.N; i++ {\n_ = m[inputs[i%len(inputs)]]\n}\n})\n}\n\n")
+
+	fmt.Fprintf(&buf, "var %sTests = [...]struct {\nkey string\nindex, slot int\n}{\n", name)
+	for i, kw := range l.keys {
+		fmt.Fprintf(&buf, "{%q, %d, %d},\n", kw, i, slotOf[i])
+	}
+	buf.WriteString("}\n\n")
+	fmt.Fprintf(&buf, "var %sNearMisses = [...]string{\n", name)
+	for _, s := range nearMisses(l.keys) {
+		fmt.Fprintf(&buf, "%q,\n", s)
+	}
+	buf.WriteString("}\n\n")
+	writeGoBenchInputs(&buf, name+"BenchInputs", l.keys)
+	return writeGoSource(w, buf.Bytes())
+}
+
+func writeGoKeys(buf *bytes.Buffer, name string, keys []string) {
+	fmt.Fprintf(buf, "var %s = [...]string{\n", name)
+	for _, kw := range keys {
+		fmt.Fprintf(buf, "%q,\n", kw)
+	}
+	buf.WriteString("}\n\n")
+}
+
+// writeGoSlots writes a table of key indices plus one using the smallest unsigned type that fits.
+func writeGoSlots(buf *bytes.Buffer, name string, slots []int32, numKeys int) {
+	fmt.Fprintf(buf, "var %s = [%d]%s{", name, len(slots), goUintType(uint64(numKeys)))
+	for i, slot := range slots {
+		if i%16 == 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(buf, "%d, ", slot)
+	}
+	buf.WriteString("\n}\n\n")
+}
+
+// goUintType returns the smallest Go unsigned integer type which can hold maxValue.
+func goUintType(maxValue uint64) string {
+	switch {
+	case maxValue <= 0xff:
+		return "uint8"
+	case maxValue <= 0xffff:
+		return "uint16"
+	case maxValue <= 0xffff_ffff:
+		return "uint32"
+	}
+	return "uint64"
+}
+
+// writeGoSource formats src and writes it to w. Unformattable source is written
+// as-is along with the error so that it can be inspected.
+func writeGoSource(w io.Writer, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		w.Write(src)
+		return fmt.Errorf("formatting generated code: %w", err)
+	}
+	_, err = w.Write(formatted)
+	return err
+}
@@ -0,0 +1,119 @@
+package perfect
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+func ExampleLookup_WriteGo() {
+	keywords := []string{"if", "else", "for", "return", "func", "var", "const"}
+	hasher := &HashSequential{
+		LenCoef: Coef{},
+		Coefs: []Coef{
+			{IndexApplied: 0, Op: OpXor},
+			{IndexApplied: 1, Op: OpAdd},
+		},
+	}
+	err := hasher.ConfigCoefs(16)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	const tablesizebits = 4
+	_, err = phf.Search(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	lookup, err := NewLookup(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	err = lookup.WriteGo(os.Stdout, GoConfig{Package: "lexer", Name: "keyword"})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// // Code generated by github.com/soypat/perfect. DO NOT EDIT.
+	//
+	// package lexer
+	//
+	// // keyword returns the index of s in keywordKeys or -1 if s is not a key.
+	// func keyword(s string) int {
+	// 	i := keywordSlots[keywordHash(s)&15]
+	// 	if i != 0 && keywordKeys[i-1] == s {
+	// 		return int(i - 1)
+	// 	}
+	// 	return -1
+	// }
+	//
+	// var keywordKeys = [...]string{
+	// 	"if",
+	// 	"else",
+	// 	"for",
+	// 	"return",
+	// 	"func",
+	// 	"var",
+	// 	"const",
+	// }
+	//
+	// var keywordSlots = [16]uint8{
+	// 	0, 1, 0, 0, 3, 7, 6, 5, 0, 4, 0, 0, 0, 2, 0, 0,
+	// }
+	//
+	// func keywordHash(s string) uint {
+	// 	h := uint(len(s)) * 1
+	// 	if len(s) > 0 {
+	// 		h ^= uint(s[0]) * 1
+	// 	}
+	// 	if len(s) > 1 {
+	// 		h += uint(s[1]) * 1
+	// 	}
+	// 	return h
+	// }
+}
+
+func ExampleLookup_WriteGoTest() {
+	keywords := []string{"if", "else", "for", "return", "func", "var", "const"}
+	hasher := &HashSequential{
+		Coefs: []Coef{
+			{IndexApplied: 0, Op: OpXor},
+			{IndexApplied: 1, Op: OpAdd},
+		},
+	}
+	err := hasher.ConfigCoefs(16)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	const tablesizebits = 4
+	_, err = phf.Search(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	lookup, err := NewLookup(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var buf bytes.Buffer
+	err = lookup.WriteGoTest(&buf, GoConfig{Package: "lexer", Name: "keyword"})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Print declarations of generated test file.
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, "func ") || strings.HasPrefix(line, "var ") {
+			fmt.Println(line)
+		}
+	}
+	// Output:
+	// func TestKeyword(t *testing.T) {
+	// func TestKeywordNearMiss(t *testing.T) {
+	// func FuzzKeyword(f *testing.F) {
+	// func BenchmarkKeyword(b *testing.B) {
+	// var keywordTests = [...]struct {
+	// var keywordNearMisses = [...]string{
+	// var keywordBenchInputs = [...]string{
+}
@@ -0,0 +1,187 @@
+package perfect
+
+import (
+	"errors"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// Genetic configures a population based search over [HashSequential] configurations.
+// See [HashFinder.SearchGenetic].
+type Genetic struct {
+	// Population is the number of individuals per generation. Defaults to 64.
+	Population int
+	// Generations is the maximum number of generations bred. Defaults to 1000.
+	Generations int
+	// Elite is the number of fittest individuals carried over unchanged to the
+	// next generation. Defaults to an eighth of Population.
+	Elite int
+	// MutationRate is the probability of each coefficient of a child being mutated. Defaults to 0.2.
+	MutationRate float64
+	// MaxValue bounds coefficient values to [1, MaxValue). Defaults to 64.
+	MaxValue uint
+	// MaxIndex, if positive, lets mutations change a coefficient's IndexApplied to
+	// any index in [-MaxIndex, MaxIndex). Zero keeps the indices of the initial hash.
+	MaxIndex int
+	// Workers is the number of goroutines scoring individuals. Defaults to GOMAXPROCS.
+	Workers int
+	// Seed seeds breeding so searches are reproducible regardless of Workers.
+	Seed uint64
+}
+
+// individual is a scored member of a genetic search population.
+type individual struct {
+	hs         *HashSequential
+	collisions int
+}
+
+// SearchGenetic searches for a perfect hash with a genetic algorithm. Individuals are
+// configurations with the same number of coefficients as hs, starting from hs and random
+// configurations, and are scored by their number of colliding inputs in parallel. Each
+// generation keeps the elite and breeds the rest by tournament selection, uniform crossover
+// of coefficients and mutation of their value, operation and index.
+//
+// hs is not modified. Like [HashFinder.SearchBest] it returns [ErrNoCoefficientsFound]
+// with the best configuration and its colliding inputs if no perfect hash was found.
+// Attempts counts scored individuals.
+func (phf *HashFinder) SearchGenetic(hs *HashSequential, tableSizeBits int, inputs []string, cfg Genetic) (SearchResult, error) {
+	mask, err := phf.init(tableSizeBits, inputs)
+	if err != nil {
+		return SearchResult{}, err
+	} else if len(hs.Coefs) == 0 {
+		return SearchResult{}, errors.New("hash has no coefficients")
+	}
+	for i := range hs.Coefs {
+		if !hs.Coefs[i].Op.valid() {
+			return SearchResult{}, errors.New("coefficient has unknown operation, see ConfigCoefs")
+		}
+	}
+	popsize := cfg.Population
+	if popsize <= 0 {
+		popsize = 64
+	} else if popsize < 2 {
+		return SearchResult{}, errors.New("population must be at least 2")
+	}
+	generations := cfg.Generations
+	if generations <= 0 {
+		generations = 1000
+	}
+	elite := cfg.Elite
+	if elite <= 0 {
+		elite = max(1, popsize/8)
+	}
+	elite = min(elite, popsize-1)
+	mutationRate := cfg.MutationRate
+	if mutationRate <= 0 {
+		mutationRate = 0.2
+	}
+	maxValue := cfg.MaxValue
+	if maxValue == 0 {
+		maxValue = 64
+	} else if maxValue < 2 {
+		return SearchResult{}, errors.New("MaxValue must be at least 2")
+	}
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, popsize)
+	finders := make([]HashFinder, workers)
+	for i := range finders {
+		finders[i].init(tableSizeBits, inputs)
+	}
+	rng := rand.New(rand.NewPCG(cfg.Seed, 0x9e3779b97f4a7c15))
+	m := newKeyMatrix(inputs, coefPositions(hs, cfg.MaxIndex))
+
+	population := make([]individual, popsize)
+	next := make([]individual, popsize)
+	for i := range population {
+		child := hs.Clone().(*HashSequential)
+		if i > 0 {
+			mutateAll(child, rng, 1, maxValue, cfg.MaxIndex)
+		}
+		population[i].hs = child
+	}
+	var result SearchResult
+	for range generations {
+		scorePopulation(finders, population, m, mask)
+		result.Attempts += len(population)
+		slices.SortStableFunc(population, func(a, b individual) int { return a.collisions - b.collisions })
+		if population[0].collisions == 0 {
+			break
+		}
+		copy(next, population[:elite])
+		for i := elite; i < popsize; i++ {
+			a := tournament(population, rng)
+			b := tournament(population, rng)
+			next[i].hs = crossover(a.hs, b.hs, rng, next[i].hs)
+			mutateAll(next[i].hs, rng, mutationRate, maxValue, cfg.MaxIndex)
+		}
+		// Reuse the bred-out individuals' storage in the next generation.
+		population, next = next, population
+		for i := range next[:elite] {
+			next[i].hs = nil // Elite is shared with population, must not be overwritten.
+		}
+	}
+	best := population[0]
+	result.Best = best.hs.Clone()
+	if best.collisions > 0 {
+		result.Collisions = phf.collisions(result.Best, mask, inputs)
+		return result, ErrNoCoefficientsFound
+	}
+	return result, nil
+}
+
+// scorePopulation counts the collisions of every individual, splitting the work among finders.
+func scorePopulation(finders []HashFinder, population []individual, m *keyMatrix, mask uint) {
+	var wg sync.WaitGroup
+	for w := range finders {
+		wg.Add(1)
+		go func(phf *HashFinder) {
+			defer wg.Done()
+			for i := w; i < len(population); i += len(finders) {
+				population[i].collisions = phf.countCollisions(population[i].hs, m, mask)
+			}
+		}(&finders[w])
+	}
+	wg.Wait()
+}
+
+// tournament returns the fitter of two random individuals of a population sorted by fitness.
+func tournament(population []individual, rng *rand.Rand) individual {
+	return population[min(rng.IntN(len(population)), rng.IntN(len(population)))]
+}
+
+// crossover returns a child taking each coefficient from a or b at random, reusing dst's storage if not nil.
+func crossover(a, b *HashSequential, rng *rand.Rand, dst *HashSequential) *HashSequential {
+	if dst == nil {
+		dst = &HashSequential{Coefs: make([]Coef, len(a.Coefs))}
+	}
+	*dst = HashSequential{LenCoef: a.LenCoef, Coefs: dst.Coefs[:len(a.Coefs)], Width: a.Width}
+	if rng.IntN(2) == 0 {
+		dst.LenCoef = b.LenCoef
+	}
+	for i := range dst.Coefs {
+		dst.Coefs[i] = a.Coefs[i]
+		if rng.IntN(2) == 0 {
+			dst.Coefs[i] = b.Coefs[i]
+		}
+	}
+	return dst
+}
+
+// mutateAll mutates each coefficient of hs with probability rate, and at least one
+// so that children of converged parents still explore.
+func mutateAll(hs *HashSequential, rng *rand.Rand, rate float64, maxValue uint, maxIndex int) {
+	forced := rng.IntN(len(hs.Coefs) + 1)
+	if forced == 0 || rng.Float64() < rate {
+		mutateCoef(&hs.LenCoef, rng, true, maxValue, maxIndex)
+	}
+	for i := range hs.Coefs {
+		if forced == i+1 || rng.Float64() < rate {
+			mutateCoef(&hs.Coefs[i], rng, false, maxValue, maxIndex)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package perfect
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+	"testing"
+)
+
+func ExampleHashFinder_SearchGenetic() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	hasher := &HashSequential{
+		Coefs: []Coef{{IndexApplied: 0}, {IndexApplied: 1}, {IndexApplied: 2}, {IndexApplied: -1}},
+	}
+	err := hasher.ConfigCoefs(32)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	// 25 keywords in 64 slots.
+	result, err := phf.SearchGenetic(hasher, 6, keywords, Genetic{MaxValue: 32, MaxIndex: 3, Seed: 1})
+	if err != nil {
+		log.Fatalln(err, "with", len(result.Collisions), "collisions")
+	}
+	fmt.Println("attempts:", result.Attempts)
+	fmt.Print(result.Best.(*HashSequential).String())
+	// Output:
+	// attempts: 640
+	// h := uint(len(s))*1
+	// h += uint(s[len(s)-1])*1
+	// h += uint(s[1])*4
+	// h += uint(s[2])*9
+	// h += uint(s[len(s)-1])*1
+}
+
+// BenchmarkSearchStrategies compares search strategies finding a perfect hash of Go's keywords.
+func BenchmarkSearchStrategies(b *testing.B) {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	const tableBits = 6
+	newHasher := func(b *testing.B) *HashSequential {
+		hasher := &HashSequential{
+			Coefs: []Coef{{IndexApplied: 0}, {IndexApplied: 1}, {IndexApplied: 2}, {IndexApplied: -1}},
+		}
+		err := hasher.ConfigCoefs(32)
+		if err != nil {
+			b.Fatal(err)
+		}
+		return hasher
+	}
+	strategies := []struct {
+		name   string
+		search func(phf *HashFinder, hs *HashSequential, seed uint64) (SearchResult, error)
+	}{
+		{name: "exhaustive", search: func(phf *HashFinder, hs *HashSequential, seed uint64) (SearchResult, error) {
+			attempts, err := phf.Search(hs, tableBits, keywords)
+			return SearchResult{Best: hs, Attempts: attempts}, err
+		}},
+		{name: "anneal", search: func(phf *HashFinder, hs *HashSequential, seed uint64) (SearchResult, error) {
+			return phf.SearchAnneal(hs, tableBits, keywords, Anneal{Temperature: 1, MaxValue: 32, MaxIndex: 3, Seed: seed})
+		}},
+		{name: "genetic", search: func(phf *HashFinder, hs *HashSequential, seed uint64) (SearchResult, error) {
+			return phf.SearchGenetic(hs, tableBits, keywords, Genetic{MaxValue: 32, MaxIndex: 3, Seed: seed})
+		}},
+	}
+	for _, strategy := range strategies {
+		b.Run(strategy.name, func(b *testing.B) {
+			var phf HashFinder
+			attempts := 0
+			for i := 0; b.Loop(); i++ {
+				result, err := strategy.search(&phf, newHasher(b), uint64(i))
+				if err != nil {
+					b.Fatal(err)
+				}
+				attempts += result.Attempts
+			}
+			b.ReportMetric(float64(attempts)/float64(b.N), "attempts/op")
+		})
+	}
+}
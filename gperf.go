@@ -0,0 +1,231 @@
+package perfect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// HashGperf is the hash family used by GNU gperf:
+//
+//	h := len(s) + AssoValues[s[Positions[0]]] + AssoValues[s[Positions[1]]] + ...
+//
+// Unlike [HashSequential] the searched parameters are per-byte-value associated values
+// rather than one coefficient per position. Positions beyond the end of a key are skipped.
+// Use [HashFinder.SearchGperf] for gperf's iterative collision-resolution search, or
+// configure with [HashGperf.ConfigAsso] for an exhaustive [HashFinder.Search].
+type HashGperf struct {
+	// Positions are the byte indices of the key that are summed. Negative indexes from end.
+	Positions []int
+	// AssoValues is the associated value of each byte value.
+	AssoValues [256]uint
+	// MaxValue bounds associated values to [0, MaxValue). Must be a power of two.
+	MaxValue uint
+	// Jump is the step by which associated values are changed when resolving collisions. Must be odd.
+	Jump uint
+
+	chars []byte // Byte values whose associated values are incremented, set by ConfigAsso.
+}
+
+// ConfigAsso resets all associated values to zero and sets the byte values which
+// Increment iterates over to those found at the selected positions of inputs.
+func (hg *HashGperf) ConfigAsso(inputs []string) error {
+	if err := hg.validate(); err != nil {
+		return err
+	}
+	hg.AssoValues = [256]uint{}
+	var used [256]bool
+	hg.chars = hg.chars[:0]
+	for _, kw := range inputs {
+		for _, pos := range hg.Positions {
+			c, ok := byteAt(kw, pos)
+			if ok && !used[c] {
+				used[c] = true
+				hg.chars = append(hg.chars, c)
+			}
+		}
+	}
+	slices.Sort(hg.chars)
+	return nil
+}
+
+func (hg *HashGperf) validate() error {
+	if len(hg.Positions) == 0 {
+		return errors.New("gperf hash has no positions")
+	} else if hg.MaxValue == 0 || hg.MaxValue&(hg.MaxValue-1) != 0 {
+		return errors.New("gperf MaxValue must be a power of two")
+	} else if hg.Jump%2 == 0 {
+		return errors.New("gperf Jump must be odd")
+	}
+	return nil
+}
+
+// Hash computes the hash value for the given string.
+func (hg *HashGperf) Hash(dataToHash string) uint {
+	h := uint(len(dataToHash))
+	for _, pos := range hg.Positions {
+		if c, ok := byteAt(dataToHash, pos); ok {
+			h += hg.AssoValues[c]
+		}
+	}
+	return h
+}
+
+// Increment advances the associated values of the configured byte values like an odometer,
+// each in steps of Jump modulo MaxValue. Returns true when exhausted.
+func (hg *HashGperf) Increment() (done bool) {
+	mask := hg.MaxValue - 1
+	for _, c := range hg.chars {
+		hg.AssoValues[c] = (hg.AssoValues[c] + hg.Jump) & mask
+		if hg.AssoValues[c] != 0 {
+			return false
+		}
+	}
+	return true // All values wrapped around to zero.
+}
+
+// Clone returns a deep copy of hg as a [Hash].
+func (hg *HashGperf) Clone() Hash {
+	clone := *hg
+	clone.Positions = slices.Clone(hg.Positions)
+	clone.chars = slices.Clone(hg.chars)
+	return &clone
+}
+
+// SearchGperf finds associated values using gperf's iterative collision resolution.
+// Inputs are inserted one at a time, those with the most common bytes first. When an input
+// collides with an earlier one the associated values of the bytes which tell the two apart
+// are changed in steps of Jump, rarest byte first, until all inputs so far are collision free.
+// Returns the number of associated value changes tried.
+func (phf *HashFinder) SearchGperf(hg *HashGperf, tableSizeBits int, inputs []string) (int, error) {
+	mask, err := phf.init(tableSizeBits, inputs)
+	if err != nil {
+		return 0, err
+	}
+	err = hg.ConfigAsso(inputs)
+	if err != nil {
+		return 0, err
+	}
+	var occurrences [256]int
+	for _, kw := range inputs {
+		for _, pos := range hg.Positions {
+			if c, ok := byteAt(kw, pos); ok {
+				occurrences[c]++
+			}
+		}
+	}
+	keyOccurrences := func(kw string) (n int) {
+		for _, pos := range hg.Positions {
+			if c, ok := byteAt(kw, pos); ok {
+				n += occurrences[c]
+			}
+		}
+		return n
+	}
+	ordered := slices.Clone(inputs)
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		return keyOccurrences(b) - keyOccurrences(a)
+	})
+	hashmap := phf.hashmap
+	defer clear(hashmap) // Indices must not be mistaken for epochs of later searches.
+	// collisionPrior returns the index of an input before i that collides with another input up to i, or -1.
+	collisionPrior := func(i int) int {
+		clear(hashmap)
+		for j, kw := range ordered[:i+1] {
+			h := hg.Hash(kw) & mask
+			if hashmap[h] != 0 {
+				return int(hashmap[h] - 1)
+			}
+			hashmap[h] = uint(j) + 1
+		}
+		return -1
+	}
+	attempts := 0
+	maxTries := int(hg.MaxValue)
+	for i := range ordered {
+		other := collisionPrior(i)
+		if other < 0 {
+			continue
+		}
+		chars := gperfDistinguishing(hg.Positions, ordered[i], ordered[other])
+		slices.SortStableFunc(chars, func(a, b byte) int { return occurrences[a] - occurrences[b] })
+		resolved := false
+		for _, c := range chars {
+			original := hg.AssoValues[c]
+			for range maxTries - 1 {
+				attempts++
+				hg.AssoValues[c] = (hg.AssoValues[c] + hg.Jump) & (hg.MaxValue - 1)
+				if collisionPrior(i) < 0 {
+					resolved = true
+					break
+				}
+			}
+			if resolved {
+				break
+			}
+			hg.AssoValues[c] = original
+		}
+		if !resolved {
+			return attempts, fmt.Errorf("%w: can not resolve collision of %q with %q", ErrNoCoefficientsFound, ordered[i], ordered[other])
+		}
+	}
+	return attempts, nil
+}
+
+// gperfDistinguishing returns the byte values whose associated values change the hash of a
+// relative to b, that is the bytes which appear a different number of times at the positions of a and b.
+func gperfDistinguishing(positions []int, a, b string) []byte {
+	var count [256]int
+	for _, pos := range positions {
+		if c, ok := byteAt(a, pos); ok {
+			count[c]++
+		}
+		if c, ok := byteAt(b, pos); ok {
+			count[c]--
+		}
+	}
+	var chars []byte
+	for c, n := range count {
+		if n != 0 {
+			chars = append(chars, byte(c))
+		}
+	}
+	return chars
+}
+
+// byteAt returns the byte of s at idx with the same semantics as [Coef.Apply]:
+// negative indexes from the end and out of bounds indexes report false.
+func byteAt(s string, idx int) (byte, bool) {
+	if idx < 0 && -idx <= len(s) {
+		return s[len(s)+idx], true
+	} else if idx >= 0 && idx < len(s) {
+		return s[idx], true
+	}
+	return 0, false
+}
+
+// WriteGoFunc writes hg as a Go function named name along with its associated value table. See [GoHasher].
+func (hg *HashGperf) WriteGoFunc(w io.Writer, name string) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "func %s(s string) uint {\n", name)
+	buf.WriteString("h := uint(len(s))\n")
+	for _, pos := range hg.Positions {
+		if pos < 0 {
+			fmt.Fprintf(&buf, "if len(s) >= %d {\nh += uint(%sAsso[s[len(s)%d]])\n}\n", -pos, name, pos)
+		} else {
+			fmt.Fprintf(&buf, "if len(s) > %d {\nh += uint(%sAsso[s[%d]])\n}\n", pos, name, pos)
+		}
+	}
+	buf.WriteString("return h\n}\n\n")
+	fmt.Fprintf(&buf, "var %sAsso = [256]%s{", name, goUintType(uint64(hg.MaxValue-1)))
+	for i, v := range hg.AssoValues {
+		if i%16 == 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "%d, ", v)
+	}
+	buf.WriteString("\n}\n")
+	return writeGoSource(w, buf.Bytes())
+}
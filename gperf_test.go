@@ -0,0 +1,32 @@
+package perfect
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+)
+
+func ExampleHashFinder_SearchGperf() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	hasher := &HashGperf{
+		Positions: []int{0, 1, -1},
+		MaxValue:  64,
+		Jump:      5,
+	}
+	var phf HashFinder
+	const tablesizebits = 6
+	attempts, err := phf.SearchGperf(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err, "after", attempts, "attempts")
+	}
+	fmt.Printf("gperf hash for Go's %d keywords found after %d attempts\n", len(keywords), attempts)
+	fmt.Println("associated values of a-z:", hasher.AssoValues['a':'z'+1])
+	// Output:
+	// gperf hash for Go's 25 keywords found after 31 attempts
+	// associated values of a-z: [0 10 15 5 0 0 25 10 0 0 0 5 15 5 0 0 0 0 0 10 5 5 20 0 25 0]
+}
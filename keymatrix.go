@@ -0,0 +1,107 @@
+package perfect
+
+import "slices"
+
+// keyAbsent marks a byte index out of bounds of a key, where [Coef.Apply] leaves the hash unchanged.
+const keyAbsent = 0x100
+
+// keyMatrix holds the length and the bytes at a set of positions of every input so that
+// searches over [HashSequential] configurations don't index into the inputs on every attempt.
+type keyMatrix struct {
+	positions []int    // Byte indices of the columns, as in Coef.IndexApplied.
+	lens      []uint64 // Length of each input.
+	// bytes has a row per input holding its byte at each of positions, or keyAbsent.
+	bytes []uint16
+}
+
+// newKeyMatrix extracts the bytes of inputs at the given positions, repeated positions are stored once.
+func newKeyMatrix(inputs []string, positions []int) *keyMatrix {
+	m := &keyMatrix{lens: make([]uint64, len(inputs))}
+	for _, pos := range positions {
+		if !slices.Contains(m.positions, pos) {
+			m.positions = append(m.positions, pos)
+		}
+	}
+	m.bytes = make([]uint16, 0, len(inputs)*len(m.positions))
+	for k, kw := range inputs {
+		m.lens[k] = uint64(len(kw))
+		for _, pos := range m.positions {
+			b, ok := byteAt(kw, pos)
+			if ok {
+				m.bytes = append(m.bytes, uint16(b))
+			} else {
+				m.bytes = append(m.bytes, keyAbsent)
+			}
+		}
+	}
+	return m
+}
+
+// coefPositions returns the byte indices of the coefficients of hs followed by every
+// index in [-maxIndex, maxIndex), the positions searches moving coefficients may reach.
+func coefPositions(hs *HashSequential, maxIndex int) []int {
+	positions := make([]int, 0, len(hs.Coefs)+2*max(maxIndex, 0))
+	for i := range hs.Coefs {
+		positions = append(positions, hs.Coefs[i].IndexApplied)
+	}
+	for pos := -maxIndex; pos < maxIndex; pos++ {
+		positions = append(positions, pos)
+	}
+	return positions
+}
+
+// row returns the bytes of input k.
+func (m *keyMatrix) row(k int) []uint16 {
+	stride := len(m.positions)
+	return m.bytes[k*stride : k*stride+stride]
+}
+
+// columns returns the column of each coefficient of hs in the rows, reusing dst's storage.
+// The positions of the coefficients must be in the matrix.
+func (m *keyMatrix) columns(hs *HashSequential, dst []int) []int {
+	dst = dst[:0]
+	for i := range hs.Coefs {
+		dst = append(dst, slices.Index(m.positions, hs.Coefs[i].IndexApplied))
+	}
+	return dst
+}
+
+// hash is hs.Hash of input k given the columns of its coefficients. It is computed in
+// 64-bit arithmetic for every [Width], which agrees with Hash in the low 32 bits
+// from which table slots are taken.
+func (m *keyMatrix) hash(hs *HashSequential, cols []int, k int) uint64 {
+	h := m.lens[k] * uint64(hs.LenCoef.Value)
+	row := m.row(k)
+	coefs := hs.Coefs[:len(cols)]
+	for c, col := range cols {
+		b := row[col]
+		if b == keyAbsent {
+			continue
+		}
+		a := uint64(b) * uint64(coefs[c].Value)
+		switch coefs[c].Op {
+		case OpAdd:
+			h += a
+		case OpXor:
+			h ^= a
+		case OpMul:
+			h *= a
+		default:
+			panic("unsupported operation")
+		}
+	}
+	return h
+}
+
+// sequentialMatrix returns hasher and the key matrix of inputs if hasher is a [HashSequential],
+// loading the columns of its coefficients into phf.cols. Increment does not move coefficients
+// so the columns hold for the whole search. Other hashes return a nil matrix.
+func (phf *HashFinder) sequentialMatrix(hasher Hash, inputs []string) (*HashSequential, *keyMatrix) {
+	hs, ok := hasher.(*HashSequential)
+	if !ok {
+		return nil, nil
+	}
+	m := newKeyMatrix(inputs, coefPositions(hs, 0))
+	phf.cols = m.columns(hs, phf.cols)
+	return hs, m
+}
@@ -0,0 +1,193 @@
+package perfect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"slices"
+)
+
+// LengthDispatch first dispatches on the length of a key and then looks it up among
+// the keys of that length. Length groups with a single key are resolved by direct
+// comparison and larger groups get their own small searched hash. Key sets such as
+// Fortran's END, ENDDO, ENDIF and ENDTYPE are mostly separable by length, so the
+// per-length hashes are easy to find and the generated `switch len(s)` is often faster than one global hash.
+type LengthDispatch struct {
+	// Template is the configured hash searched for each length group with more than one key.
+	// Coefficient indices beyond a group's length are ignored for that group.
+	Template HashSequential
+	// MaxExtraBits is the number of bits by which a group's table may grow past the
+	// smallest power of two that fits its keys when no hash is found. Defaults to 3.
+	MaxExtraBits int
+
+	keys   []string
+	groups []lengthGroup // Sorted by length.
+	slots  []int32       // Key index plus one per slot of all group tables, zero if empty.
+}
+
+type lengthGroup struct {
+	length int
+	key    int32           // Index of key if group has a single key.
+	hash   *HashSequential // Nil for single key groups.
+	offset int             // Start of group's table in slots.
+	mask   uint
+}
+
+// SearchLengthDispatch groups inputs by length and searches a hash for each group with more than one input.
+// Returns the total number of attempts over all groups.
+func (phf *HashFinder) SearchLengthDispatch(ld *LengthDispatch, inputs []string) (int, error) {
+	if len(inputs) == 0 {
+		return 0, errors.New("zero inputs")
+	} else if len(ld.Template.Coefs) == 0 {
+		return 0, errors.New("template has no coefficients")
+	}
+	maxExtraBits := ld.MaxExtraBits
+	if maxExtraBits <= 0 {
+		maxExtraBits = 3
+	}
+	byLength := make(map[int][]int32)
+	for i, kw := range inputs {
+		byLength[len(kw)] = append(byLength[len(kw)], int32(i))
+	}
+	lengths := make([]int, 0, len(byLength))
+	for length := range byLength {
+		lengths = append(lengths, length)
+	}
+	slices.Sort(lengths)
+
+	ld.keys = inputs
+	ld.groups = ld.groups[:0]
+	ld.slots = ld.slots[:0]
+	attempts := 0
+	var groupKeys []string
+	for _, length := range lengths {
+		idxs := byLength[length]
+		if len(idxs) == 1 {
+			ld.groups = append(ld.groups, lengthGroup{length: length, key: idxs[0]})
+			continue
+		}
+		groupKeys = groupKeys[:0]
+		for _, i := range idxs {
+			groupKeys = append(groupKeys, inputs[i])
+		}
+		hasher := ld.Template.Clone().(*HashSequential)
+		minbits := max(1, bits.Len(uint(len(idxs)-1)))
+		tblbits := minbits
+		for {
+			err := hasher.ConfigCoefs(0)
+			if err != nil {
+				return attempts, fmt.Errorf("template not configured: %w", err)
+			}
+			err = ErrNoCoefficientsFound
+			if lowBitsDistinct(hasher, tblbits, groupKeys) {
+				var a int
+				a, err = phf.Search(hasher, tblbits, groupKeys)
+				attempts += a
+			}
+			if err == nil {
+				break
+			} else if !errors.Is(err, ErrNoCoefficientsFound) || tblbits >= minbits+maxExtraBits {
+				return attempts, fmt.Errorf("length %d group of %d keys: %w", length, len(idxs), err)
+			}
+			tblbits++
+		}
+		group := lengthGroup{length: length, hash: hasher, offset: len(ld.slots), mask: 1<<tblbits - 1}
+		ld.slots = append(ld.slots, make([]int32, 1<<tblbits)...)
+		for _, i := range idxs {
+			ld.slots[group.offset+int(hasher.Hash(inputs[i])&group.mask)] = i + 1
+		}
+		ld.groups = append(ld.groups, group)
+	}
+	return attempts, nil
+}
+
+// Find returns the index of s in the keys ld was searched with.
+// If s is not a key then ok is false.
+func (ld *LengthDispatch) Find(s string) (index int, ok bool) {
+	g, found := slices.BinarySearchFunc(ld.groups, len(s), func(g lengthGroup, length int) int { return g.length - length })
+	if !found {
+		return -1, false
+	}
+	group := &ld.groups[g]
+	i := group.key
+	if group.hash != nil {
+		i = ld.slots[group.offset+int(group.hash.Hash(s)&group.mask)] - 1
+	}
+	if i < 0 || ld.keys[i] != s {
+		return -1, false
+	}
+	return int(i), true
+}
+
+// WriteGo writes a Go source file to w declaring a function `func Name(s string) int`
+// which returns the index of s in the keys ld was searched with, or -1 if s is not a key.
+// The generated function switches on the length of s, so byte accesses need no bounds checks.
+func (ld *LengthDispatch) WriteGo(w io.Writer, cfg GoConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	} else if len(ld.groups) == 0 {
+		return errors.New("length dispatch not searched")
+	}
+	name := cfg.Name
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, genHeader+"package %s\n\n", cfg.Package)
+	fmt.Fprintf(&buf, "// %s returns the index of s in %sKeys or -1 if s is not a key.\n", name, name)
+	fmt.Fprintf(&buf, "func %s(s string) int {\n", name)
+	buf.WriteString("switch len(s) {\n")
+	for _, group := range ld.groups {
+		fmt.Fprintf(&buf, "case %d:\n", group.length)
+		if group.hash == nil {
+			fmt.Fprintf(&buf, "if s == %q {\nreturn %d\n}\n", ld.keys[group.key], group.key)
+			continue
+		}
+		width := group.hash.Width
+		fmt.Fprintf(&buf, "h := %s(%d)\n", width, width.wrap(uint64(group.length)*uint64(group.hash.LenCoef.Value)))
+		for _, c := range group.hash.Coefs {
+			idx := c.IndexApplied
+			if idx < 0 {
+				idx += group.length
+			}
+			if idx < 0 || idx >= group.length {
+				continue // Coefficient not applied for keys of this length.
+			}
+			fmt.Fprintf(&buf, "h %s= %s(s[%d]) * %d\n", c.Op, width, idx, width.wrap(uint64(c.Value)))
+		}
+		slot := fmt.Sprintf("h&%d", group.mask)
+		if group.offset > 0 {
+			slot = fmt.Sprintf("%d+%s", group.offset, slot)
+		}
+		fmt.Fprintf(&buf, "if i := %sSlots[%s]; i != 0 && %sKeys[i-1] == s {\nreturn int(i - 1)\n}\n", name, slot, name)
+	}
+	buf.WriteString("}\nreturn -1\n}\n\n")
+	writeGoKeys(&buf, name+"Keys", ld.keys)
+	if len(ld.slots) > 0 {
+		writeGoSlots(&buf, name+"Slots", ld.slots, len(ld.keys))
+	}
+	return writeGoSource(w, buf.Bytes())
+}
+
+// lowBitsDistinct reports whether keys of equal length can possibly be told apart by hs
+// in a table of 2^tableSizeBits slots. Addition, XOR and multiplication carry only towards
+// higher bits so the low bits of the hash depend only on the low bits of the sampled bytes.
+// Keys whose sampled bytes agree on those bits collide for all coefficient values.
+func lowBitsDistinct(hs *HashSequential, tableSizeBits int, keys []string) bool {
+	mask := byte(0xff)
+	if tableSizeBits < 8 {
+		mask = byte(1)<<tableSizeBits - 1
+	}
+	seen := make(map[string]struct{}, len(keys))
+	sig := make([]byte, len(hs.Coefs))
+	for _, kw := range keys {
+		for i, c := range hs.Coefs {
+			b, _ := byteAt(kw, c.IndexApplied)
+			sig[i] = b & mask
+		}
+		if _, dup := seen[string(sig)]; dup {
+			return false
+		}
+		seen[string(sig)] = struct{}{}
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package perfect
+
+import (
+	"log"
+	"os"
+)
+
+func ExampleHashFinder_SearchLengthDispatch() {
+	keywords := []string{"END", "ENDDO", "ENDIF", "ENDTYPE", "DO", "IF", "TYPE", "GOTO", "STOP", "EXIT", "CALL"}
+	ld := &LengthDispatch{
+		Template: HashSequential{
+			Coefs: []Coef{
+				{IndexApplied: 0, Op: OpXor},
+				{IndexApplied: -1, Op: OpAdd},
+			},
+		},
+	}
+	err := ld.Template.ConfigCoefs(16)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	attempts, err := phf.SearchLengthDispatch(ld, keywords)
+	if err != nil {
+		log.Fatalln(err, "after", attempts, "attempts")
+	}
+	err = ld.WriteGo(os.Stdout, GoConfig{Package: "lexer", Name: "keyword"})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// // Code generated by github.com/soypat/perfect. DO NOT EDIT.
+	//
+	// package lexer
+	//
+	// // keyword returns the index of s in keywordKeys or -1 if s is not a key.
+	// func keyword(s string) int {
+	// 	switch len(s) {
+	// 	case 2:
+	// 		h := uint(2)
+	// 		h ^= uint(s[0]) * 2
+	// 		h += uint(s[1]) * 1
+	// 		if i := keywordSlots[h&1]; i != 0 && keywordKeys[i-1] == s {
+	// 			return int(i - 1)
+	// 		}
+	// 	case 3:
+	// 		if s == "END" {
+	// 			return 0
+	// 		}
+	// 	case 4:
+	// 		h := uint(4)
+	// 		h ^= uint(s[0]) * 5
+	// 		h += uint(s[3]) * 1
+	// 		if i := keywordSlots[2+h&7]; i != 0 && keywordKeys[i-1] == s {
+	// 			return int(i - 1)
+	// 		}
+	// 	case 5:
+	// 		h := uint(5)
+	// 		h ^= uint(s[0]) * 1
+	// 		h += uint(s[4]) * 1
+	// 		if i := keywordSlots[10+h&1]; i != 0 && keywordKeys[i-1] == s {
+	// 			return int(i - 1)
+	// 		}
+	// 	case 7:
+	// 		if s == "ENDTYPE" {
+	// 			return 3
+	// 		}
+	// 	}
+	// 	return -1
+	// }
+	//
+	// var keywordKeys = [...]string{
+	// 	"END",
+	// 	"ENDDO",
+	// 	"ENDIF",
+	// 	"ENDTYPE",
+	// 	"DO",
+	// 	"IF",
+	// 	"TYPE",
+	// 	"GOTO",
+	// 	"STOP",
+	// 	"EXIT",
+	// 	"CALL",
+	// }
+	//
+	// var keywordSlots = [12]uint8{
+	// 	6, 5, 0, 10, 0, 9, 0, 7, 8, 11, 3, 2,
+	// }
+}
@@ -0,0 +1,86 @@
+package perfect
+
+import "errors"
+
+// Lookup maps a static set of keys to their index in the set using a hash table.
+// Keys that land on an occupied slot are kept in a short overflow list which is
+// searched by comparison, so a near-perfect hash found by [HashFinder.SearchBest]
+// can be used as well as a perfect one.
+type Lookup struct {
+	hasher   Hash
+	mask     uint
+	slots    []int32 // Key index plus one per table slot, zero if slot is empty.
+	keys     []string
+	overflow []int32 // Indices of keys which collided, compared in order.
+}
+
+// NewLookup builds a lookup for keys over a table of 2^tableSizeBits slots using hasher.
+// hasher is used as-is and must not be incremented or modified after the call.
+// Keys must be unique.
+func NewLookup(hasher Hash, tableSizeBits int, keys []string) (*Lookup, error) {
+	if tableSizeBits <= 0 || tableSizeBits > 32 {
+		return nil, errors.New("zero/negative bits for table size or too large")
+	} else if len(keys) == 0 {
+		return nil, errors.New("zero inputs")
+	}
+	tblsz := 1 << tableSizeBits
+	l := &Lookup{
+		hasher: hasher,
+		mask:   uint(tblsz) - 1,
+		slots:  make([]int32, tblsz),
+		keys:   keys,
+	}
+	seen := make(map[string]struct{}, len(keys))
+	for i, kw := range keys {
+		if _, dup := seen[kw]; dup {
+			return nil, errors.New("duplicate key " + kw)
+		}
+		seen[kw] = struct{}{}
+		h := hasher.Hash(kw) & l.mask
+		if l.slots[h] == 0 {
+			l.slots[h] = int32(i) + 1
+		} else {
+			l.overflow = append(l.overflow, int32(i))
+		}
+	}
+	return l, nil
+}
+
+// Find returns the index of s in the keys the lookup was built with.
+// If s is not a key then ok is false.
+func (l *Lookup) Find(s string) (index int, ok bool) {
+	h := l.hasher.Hash(s) & l.mask
+	if i := l.slots[h]; i != 0 && l.keys[i-1] == s {
+		return int(i - 1), true
+	}
+	for _, i := range l.overflow {
+		if l.keys[i] == s {
+			return int(i), true
+		}
+	}
+	return -1, false
+}
+
+// Overflow returns the keys which did not get a slot of their own and are
+// resolved by comparison. It is empty when the lookup's hash is perfect for its keys.
+func (l *Lookup) Overflow() []string {
+	var overflow []string
+	for _, i := range l.overflow {
+		overflow = append(overflow, l.keys[i])
+	}
+	return overflow
+}
+
+// keySlots returns the slot of each key, or -1 for keys in the overflow list.
+func (l *Lookup) keySlots() []int {
+	slotOf := make([]int, len(l.keys))
+	for i := range slotOf {
+		slotOf[i] = -1
+	}
+	for slot, i := range l.slots {
+		if i != 0 {
+			slotOf[i-1] = slot
+		}
+	}
+	return slotOf
+}
@@ -0,0 +1,54 @@
+package perfect
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"log"
+)
+
+func ExampleHashFinder_SearchBest() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	var phf HashFinder
+	hasher := &HashSequential{
+		LenCoef: Coef{OnlyPow2: true},
+		Coefs: []Coef{
+			{IndexApplied: 0, OnlyPow2: true, Op: OpXor},
+			{IndexApplied: 1, OnlyPow2: true, Op: OpXor},
+		},
+	}
+	err := hasher.ConfigCoefs(16)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Table too small for a perfect hash in this search space.
+	const tablesizebits = 5
+	result, err := phf.SearchBest(hasher, tablesizebits, keywords)
+	if !errors.Is(err, ErrNoCoefficientsFound) {
+		log.Fatalln("expected no perfect hash, got", err)
+	}
+	fmt.Printf("best of %d attempts has %d collisions %q:\n%s", result.Attempts, len(result.Collisions), result.Collisions, result.Best)
+	lookup, err := NewLookup(result.Best, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("overflow: %q\n", lookup.Overflow())
+	for _, s := range []string{"func", "select", "foo"} {
+		idx, ok := lookup.Find(s)
+		fmt.Println(s, idx, ok)
+	}
+	// Output:
+	// best of 80 attempts has 3 collisions ["for" "select" "switch"]:
+	// h := uint(len(s))*8
+	// h ^= uint(s[0])*1
+	// h ^= uint(s[1])*4
+	// overflow: ["for" "select" "switch"]
+	// func 10 true
+	// select 20 true
+	// foo -1 false
+}
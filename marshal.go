@@ -0,0 +1,229 @@
+package perfect
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Text forms. HashSequential, Coef and Op implement encoding.TextMarshaler and
+// encoding.TextUnmarshaler, which encoding/json also uses, with the textual form
+// of [HashSequential.String]:
+//
+//	h := uint(len(s))*8
+//	h ^= uint(s[0])*1
+//	h += uint(s[len(s)-1])*3
+//
+// The form of a Coef is a single `h op= ...` line, or the `h := ...` line of a
+// length coefficient, which has a zero Op. The form of an Op is its symbol.
+// Only the hash configuration is kept; search bounds such as MaxValue are not.
+
+// MarshalText returns the [HashSequential.String] form of hs.
+func (hs HashSequential) MarshalText() ([]byte, error) {
+	if !hs.Width.valid() {
+		return nil, errors.New("unknown hash width")
+	}
+	for i := range hs.Coefs {
+		if !hs.Coefs[i].Op.valid() {
+			return nil, errors.New("unknown coefficient operation")
+		}
+	}
+	return []byte(hs.String()), nil
+}
+
+// UnmarshalText sets hs to the hash parsed by [ParseHashSequential].
+func (hs *HashSequential) UnmarshalText(text []byte) error {
+	parsed, err := ParseHashSequential(string(text))
+	if err != nil {
+		return err
+	}
+	*hs = *parsed
+	return nil
+}
+
+// ParseHashSequential parses the textual form produced by [HashSequential.String].
+// Statements are separated by newlines or semicolons and may contain arbitrary spaces,
+// so `h := uint(len(s))*8; h ^= uint(s[0])*1` is also accepted. Values may be written
+// in any base understood by [strconv.ParseUint] with base 0.
+func ParseHashSequential(text string) (*HashSequential, error) {
+	stmts := strings.FieldsFunc(text, func(r rune) bool { return r == '\n' || r == ';' })
+	var hs HashSequential
+	first := true
+	for _, stmt := range stmts {
+		stmt = stripSpace(stmt)
+		if stmt == "" {
+			continue
+		}
+		if first {
+			first = false
+			width, v, err := parseLenStatement(stmt)
+			if err != nil {
+				return nil, err
+			}
+			hs.Width = width
+			hs.LenCoef.Value = v
+			continue
+		}
+		var c Coef
+		width, err := c.parse(stmt)
+		if err != nil {
+			return nil, err
+		} else if width != hs.Width {
+			return nil, fmt.Errorf("statement %q type does not match hash width %s", stmt, hs.Width)
+		}
+		hs.Coefs = append(hs.Coefs, c)
+	}
+	if first {
+		return nil, errors.New("empty hash")
+	}
+	return &hs, nil
+}
+
+// MarshalText returns c as a statement of the [HashSequential.String] form.
+// A coefficient with a zero Op and index, such as [HashSequential.LenCoef], is written
+// as the length statement `h := uint(len(s))*N`. Other coefficients need a valid Op.
+func (c Coef) MarshalText() ([]byte, error) {
+	if c.Op == opUndefined && c.IndexApplied == 0 {
+		return fmt.Appendf(nil, "h := %s(len(s))*%d", WidthUint, c.Value), nil
+	} else if !c.Op.valid() {
+		return nil, errors.New("unknown coefficient operation")
+	}
+	return []byte(c.statement(WidthUint)), nil
+}
+
+// UnmarshalText parses a statement such as `h ^= uint(s[len(s)-1])*3` into c.
+// A length statement such as `h := uint(len(s))*8` sets a zero Op and index.
+// The index, operation and value of c are set, other fields are left unchanged.
+func (c *Coef) UnmarshalText(text []byte) error {
+	stmt := stripSpace(string(text))
+	if strings.HasPrefix(stmt, "h:=") {
+		_, v, err := parseLenStatement(stmt)
+		if err != nil {
+			return err
+		}
+		c.IndexApplied = 0
+		c.Op = opUndefined
+		c.Value = v
+		return nil
+	}
+	_, err := c.parse(stmt)
+	return err
+}
+
+// parseLenStatement parses a space-free length statement `h:=uint(len(s))*N`
+// and returns its width and value.
+func parseLenStatement(stmt string) (Width, uint, error) {
+	rest, ok := strings.CutPrefix(stmt, "h:=")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected first statement of form h := uint(len(s))*N, got %q", stmt)
+	}
+	typ, value, ok := strings.Cut(rest, "(len(s))*")
+	if !ok {
+		return 0, 0, fmt.Errorf("expected length statement of form h := uint(len(s))*N, got %q", stmt)
+	}
+	width, err := parseWidth(typ)
+	if err != nil {
+		return 0, 0, err
+	}
+	v, err := parseCoefValue(value)
+	if err != nil {
+		return 0, 0, err
+	}
+	return width, v, nil
+}
+
+// parse parses a space-free coefficient statement into c and returns its width.
+func (c *Coef) parse(stmt string) (Width, error) {
+	errFormat := fmt.Errorf("expected statement of form h op= uint(s[i])*N, got %q", stmt)
+	rest, ok := strings.CutPrefix(stmt, "h")
+	if !ok || len(rest) < 2 || rest[1] != '=' {
+		return 0, errFormat
+	}
+	var op Op
+	err := op.UnmarshalText([]byte(rest[:1]))
+	if err != nil {
+		return 0, err
+	}
+	typ, rest, ok := strings.Cut(rest[2:], "(s[")
+	if !ok {
+		return 0, errFormat
+	}
+	idxstr, value, ok := strings.Cut(rest, "])*")
+	if !ok {
+		return 0, errFormat
+	}
+	width, err := parseWidth(typ)
+	if err != nil {
+		return 0, err
+	}
+	fromEnd := false
+	if s, ok := strings.CutPrefix(idxstr, "len(s)"); ok {
+		fromEnd = true
+		idxstr = s
+	}
+	idx, err := strconv.Atoi(idxstr)
+	if err != nil {
+		return 0, fmt.Errorf("invalid index in %q: %w", stmt, err)
+	} else if fromEnd != (idx < 0) {
+		return 0, fmt.Errorf("index in %q must be non-negative or of form len(s)-N", stmt)
+	}
+	v, err := parseCoefValue(value)
+	if err != nil {
+		return 0, err
+	}
+	c.IndexApplied = idx
+	c.Op = op
+	c.Value = v
+	return width, nil
+}
+
+// MarshalText returns the symbol of op as used in [HashSequential.String].
+func (op Op) MarshalText() ([]byte, error) {
+	if !op.valid() {
+		return nil, errors.New("unknown operation")
+	}
+	return []byte(op.String()), nil
+}
+
+// UnmarshalText parses an operation symbol: "+", "^" or "*".
+func (op *Op) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "+":
+		*op = OpAdd
+	case "^":
+		*op = OpXor
+	case "*":
+		*op = OpMul
+	default:
+		return fmt.Errorf("unknown operation %q", text)
+	}
+	return nil
+}
+
+func parseWidth(typ string) (Width, error) {
+	for _, width := range []Width{WidthUint, Width32, Width64} {
+		if typ == width.String() {
+			return width, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown hash type %q", typ)
+}
+
+func parseCoefValue(s string) (uint, error) {
+	v, err := strconv.ParseUint(s, 0, strconv.IntSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid coefficient value: %w", err)
+	}
+	return uint(v), nil
+}
+
+// stripSpace returns s with all spaces and tabs removed.
+func stripSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\t' || r == '\r' {
+			return -1
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,76 @@
+package perfect
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+func ExampleParseHashSequential() {
+	// Hash as passed on a command line flag.
+	hasher, err := ParseHashSequential("h := uint(len(s))*8; h ^= uint(s[0])*1; h ^= uint(s[1])*8")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(hasher.Hash("func"))
+
+	// Hash stored in a JSON configuration file.
+	type config struct {
+		Keywords []string
+		Hash     *HashSequential
+	}
+	b, err := json.Marshal(config{Keywords: []string{"if", "func"}, Hash: hasher})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(string(b))
+	var cfg config
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Print(cfg.Hash.String())
+	fmt.Println(cfg.Hash.Hash("func"))
+	// Output:
+	// 1006
+	// {"Keywords":["if","func"],"Hash":"h := uint(len(s))*8\nh ^= uint(s[0])*1\nh ^= uint(s[1])*8\n"}
+	// h := uint(len(s))*8
+	// h ^= uint(s[0])*1
+	// h ^= uint(s[1])*8
+	// 1006
+}
+
+func ExampleHashSequential_MarshalText() {
+	hasher, err := ParseHashSequential("h := uint32(len(s))*8; h ^= uint32(s[0])*1; h += uint32(s[len(s)-1])*3")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Hash stored by value and its coefficients, including the length coefficient.
+	type config struct {
+		H       HashSequential
+		LenCoef Coef
+		Coefs   []Coef
+	}
+	b, err := json.Marshal(config{H: *hasher, LenCoef: hasher.LenCoef, Coefs: hasher.Coefs})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Println(string(b))
+	var cfg config
+	err = json.Unmarshal(b, &cfg)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Print(cfg.H.String())
+	fmt.Println(cfg.H.Hash("func") == hasher.Hash("func"), cfg.LenCoef == hasher.LenCoef, len(cfg.Coefs))
+	// Only the length coefficient may lack an operation, other coefficients would lose their index.
+	_, err = json.Marshal(Coef{IndexApplied: 3, Value: 5})
+	fmt.Println(err)
+	// Output:
+	// {"H":"h := uint32(len(s))*8\nh ^= uint32(s[0])*1\nh += uint32(s[len(s)-1])*3\n","LenCoef":"h := uint(len(s))*8","Coefs":["h ^= uint(s[0])*1","h += uint(s[len(s)-1])*3"]}
+	// h := uint32(len(s))*8
+	// h ^= uint32(s[0])*1
+	// h += uint32(s[len(s)-1])*3
+	// true true 2
+	// json: error calling MarshalText for type *perfect.Coef: unknown coefficient operation
+}
@@ -0,0 +1,120 @@
+package perfect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+)
+
+// HashPearson is a Pearson hash family. Every byte of the key is read through a
+// 256-entry permutation table, so it can discriminate keys that differ at positions
+// a [HashSequential] does not sample. The searched parameter is the permutation itself,
+// a shuffle of the identity selected by a seed which Increment advances.
+//
+// Each pass j computes an 8-bit hash
+//
+//	h := byte(j)
+//	for i := range len(s) { h = Table[h^s[i]] }
+//
+// and the results of all passes are concatenated, first pass in the lowest byte.
+type HashPearson struct {
+	Table [256]byte // Current permutation, set from Seed.
+	// Passes is the number of 8-bit hashes concatenated into the result, 1 to 4.
+	// Tables of more than 256 slots need more than one pass.
+	Passes int
+	// Affine makes Seed enumerate the affine permutations Table[x] = x*(2*(Seed/256)+1) + Seed%256
+	// instead of shuffles. The low n bits of an affine permutation depend only on the low n bits
+	// of its input, so keys whose bytes differ only in high bits, such as "a1" and "aq",
+	// collide in tables of up to 2^6 slots whatever the seed.
+	Affine bool
+	// Seed selects the current permutation. Incremented on each Increment.
+	Seed uint64
+	// MaxSeed is the seed at which the search is exhausted. Defaults to the
+	// number of affine permutations, 2^15, which is also its maximum in affine mode.
+	MaxSeed uint64
+}
+
+// pearsonAffinePerms is the number of affine permutations of a byte: 128 odd multipliers times 256 offsets.
+const pearsonAffinePerms = 128 * 256
+
+// ConfigTable validates hp's configuration and initializes the permutation table from Seed.
+func (hp *HashPearson) ConfigTable() error {
+	if hp.Passes < 1 || hp.Passes > 4 {
+		return errors.New("pearson passes must be between 1 and 4")
+	}
+	if hp.MaxSeed == 0 || (hp.Affine && hp.MaxSeed > pearsonAffinePerms) {
+		hp.MaxSeed = pearsonAffinePerms
+	}
+	if hp.Seed >= hp.MaxSeed {
+		return errors.New("pearson seed must be less than MaxSeed")
+	}
+	hp.permute()
+	return nil
+}
+
+// Hash computes the hash value for the given string.
+func (hp *HashPearson) Hash(dataToHash string) uint {
+	var result uint
+	for j := range hp.Passes {
+		h := byte(j)
+		for i := 0; i < len(dataToHash); i++ {
+			h = hp.Table[h^dataToHash[i]]
+		}
+		result |= uint(h) << (8 * j)
+	}
+	return result
+}
+
+// Increment advances to the permutation of the next seed. Returns true when exhausted.
+func (hp *HashPearson) Increment() (done bool) {
+	hp.Seed++
+	hp.permute()
+	return hp.Seed >= hp.MaxSeed
+}
+
+// Clone returns a copy of hp as a [Hash].
+func (hp *HashPearson) Clone() Hash {
+	clone := *hp
+	return &clone
+}
+
+func (hp *HashPearson) permute() {
+	if hp.Affine {
+		mul, add := 2*byte(hp.Seed/256)+1, byte(hp.Seed)
+		for i := range hp.Table {
+			hp.Table[i] = byte(i)*mul + add
+		}
+		return
+	}
+	for i := range hp.Table {
+		hp.Table[i] = byte(i)
+	}
+	rng := rand.New(rand.NewPCG(hp.Seed, 0))
+	rng.Shuffle(len(hp.Table), func(i, j int) {
+		hp.Table[i], hp.Table[j] = hp.Table[j], hp.Table[i]
+	})
+}
+
+// WriteGoFunc writes hp as a Go function named name along with its permutation table. See [GoHasher].
+func (hp *HashPearson) WriteGoFunc(w io.Writer, name string) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "func %s(s string) uint {\n", name)
+	if hp.Passes == 1 {
+		fmt.Fprintf(&buf, "var h byte\nfor i := 0; i < len(s); i++ {\nh = %sTable[h^s[i]]\n}\nreturn uint(h)\n}\n\n", name)
+	} else {
+		fmt.Fprintf(&buf, "var result uint\nfor j := range %d {\n", hp.Passes)
+		fmt.Fprintf(&buf, "h := byte(j)\nfor i := 0; i < len(s); i++ {\nh = %sTable[h^s[i]]\n}\n", name)
+		buf.WriteString("result |= uint(h) << (8 * j)\n}\nreturn result\n}\n\n")
+	}
+	fmt.Fprintf(&buf, "var %sTable = [256]byte{", name)
+	for i, b := range hp.Table {
+		if i%16 == 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "%d, ", b)
+	}
+	buf.WriteString("\n}\n")
+	return writeGoSource(w, buf.Bytes())
+}
@@ -0,0 +1,49 @@
+package perfect
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+)
+
+func ExampleHashPearson() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	hasher := &HashPearson{Passes: 1, MaxSeed: 10000}
+	err := hasher.ConfigTable()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	const tablesizebits = 6
+	attempts, err := phf.Search(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err, "after", attempts, "attempts")
+	}
+	fmt.Printf("pearson hash for Go's %d keywords found after %d attempts with seed %d\n", len(keywords), attempts, hasher.Seed)
+	// Output:
+	// pearson hash for Go's 25 keywords found after 53 attempts with seed 52
+}
+
+func ExampleHashPearson_affine() {
+	// Keys differing only in bit 6 of their last byte.
+	keys := []string{"a1", "aq", "b2", "br"}
+	for _, affine := range []bool{true, false} {
+		hasher := &HashPearson{Passes: 1, Affine: affine}
+		err := hasher.ConfigTable()
+		if err != nil {
+			log.Fatalln(err)
+		}
+		var phf HashFinder
+		const tablesizebits = 6
+		attempts, err := phf.Search(hasher, tablesizebits, keys)
+		fmt.Printf("affine=%v: %d attempts, err=%v\n", affine, attempts, err)
+	}
+	// Output:
+	// affine=true: 32768 attempts, err=no coefficients found
+	// affine=false: 1 attempts, err=<nil>
+}
@@ -16,19 +16,92 @@ type Hash interface {
 	Increment() (done bool)
 }
 
+// Cloner is implemented by Hash types that can copy their current configuration.
+// Searches that report a configuration other than the one being incremented require it.
+type Cloner interface {
+	Hash
+	Clone() Hash
+}
+
 // HashFinder searches for perfect hash coefficients.
 type HashFinder struct {
+	// KeepOrder makes [HashFinder.Search] hash inputs in the order given. By default
+	// inputs likely to collide are hashed first so failing attempts are rejected sooner.
+	// The order is computed once and refined by every search over the same inputs.
+	// It does not change the result, only the time it takes.
+	KeepOrder bool
+
+	order       []int32  // Indices of inputs in hashing order.
+	orderInputs []string // Inputs order was computed for.
+	// hashmap slots are occupied in the current attempt if they hold epoch, so
+	// attempts rejected at their first keys don't pay for clearing the whole table.
 	hashmap []uint
+	epoch   uint
+	cols    []int // Columns of the keyMatrix read by the configuration being scored.
+}
+
+// SearchResult is the outcome of a search that tracks the best hash configuration seen.
+type SearchResult struct {
+	Best       Hash     // Copy of the configuration with the fewest collisions.
+	Attempts   int      // Number of configurations tried.
+	Collisions []string // Inputs that landed on an occupied slot under Best, in input order.
 }
 
 // HashSequential computes: h = len(s)*LenCoef + op(s[i])*Coefs[i] for each coefficient.
 type HashSequential struct {
 	LenCoef Coef
 	Coefs   []Coef
+	// Width is the width at which the hash arithmetic wraps around. The zero value
+	// wraps at the width of uint, so the hash of a key differs between 32 and 64-bit
+	// platforms. Fix it for hashes that run on more than one architecture.
+	Width Width
+}
+
+// Width is the number of bits of the unsigned integer in which hash arithmetic is performed.
+//
+// Addition, XOR and multiplication carry only towards higher bits so the low 32 bits of
+// a hash are the same for every width. Slots of power of two tables agree across widths and
+// only uses of the full value, such as the modulo of [OrderPreserving], depend on the width.
+type Width int
+
+const (
+	WidthUint Width = 0  // Width of Go's uint on the platform: 32 or 64 bits.
+	Width32   Width = 32 // uint32 arithmetic on every platform.
+	// uint64 arithmetic on every platform. Where uint is 32 bits wide
+	// Hash returns the low 32 bits of the 64-bit result.
+	Width64 Width = 64
+)
+
+func (wd Width) String() (s string) {
+	switch wd {
+	case WidthUint:
+		s = "uint"
+	case Width32:
+		s = "uint32"
+	case Width64:
+		s = "uint64"
+	default:
+		s = "<unknownwidth>"
+	}
+	return s
+}
+
+// valid reports whether wd is one of the defined widths.
+func (wd Width) valid() bool { return wd == WidthUint || wd == Width32 || wd == Width64 }
+
+// wrap truncates v to the width. Results of native width are returned unchanged.
+func (wd Width) wrap(v uint64) uint64 {
+	if wd == Width32 {
+		return uint64(uint32(v))
+	}
+	return v
 }
 
 // ConfigCoefs initializes all coefficients and sets MaxValue to defaultMax where unset.
 func (hs *HashSequential) ConfigCoefs(defaultMax uint) error {
+	if !hs.Width.valid() {
+		return errors.New("hash width must be 0, 32 or 64 bits")
+	}
 	coefs := hs.Coefs
 	for i := range coefs {
 		err := coefs[i].config(defaultMax)
@@ -40,24 +113,62 @@ func (hs *HashSequential) ConfigCoefs(defaultMax uint) error {
 }
 
 func (hs *HashSequential) String() string {
-	s := fmt.Sprintf("h := uint(len(s))*%d\n", hs.LenCoef.Value)
+	s := fmt.Sprintf("h := %s(len(s))*%d\n", hs.Width, hs.Width.wrap(uint64(hs.LenCoef.Value)))
 	for _, c := range hs.Coefs {
-		pfx := ""
-		if c.IndexApplied < 0 {
-			pfx = "len(s)"
-		}
-		s += fmt.Sprintf("h %s= uint(s[%s%d])*%d\n", c.Op.String(), pfx, c.IndexApplied, c.Value)
+		s += c.statement(hs.Width) + "\n"
 	}
 	return s
 }
 
+// statement returns the Go statement applying c to a hash h of the given width.
+func (c Coef) statement(width Width) string {
+	pfx := ""
+	if c.IndexApplied < 0 {
+		pfx = "len(s)"
+	}
+	return fmt.Sprintf("h %s= %s(s[%s%d])*%d", c.Op.String(), width, pfx, c.IndexApplied, width.wrap(uint64(c.Value)))
+}
+
+// Clone returns a deep copy of hs as a [Hash].
+func (hs *HashSequential) Clone() Hash {
+	clone := *hs
+	clone.Coefs = slices.Clone(hs.Coefs)
+	return &clone
+}
+
 // Hash computes the hash value for the given string.
 func (hs *HashSequential) Hash(dataToHash string) uint {
-	h := uint(len(dataToHash)) * hs.LenCoef.Value
+	if hs.Width == WidthUint {
+		h := uint(len(dataToHash)) * hs.LenCoef.Value
+		for i := range hs.Coefs {
+			h = hs.Coefs[i].Apply(h, dataToHash)
+		}
+		return h
+	}
+	h := uint64(len(dataToHash)) * uint64(hs.LenCoef.Value)
 	for i := range hs.Coefs {
-		h = hs.Coefs[i].Apply(h, dataToHash)
+		h = hs.Coefs[i].apply64(h, dataToHash)
 	}
-	return h
+	return uint(hs.Width.wrap(h))
+}
+
+// hashValues computes the hash of s with hs's operations, indices and width
+// but the coefficient values in values, starting with the length coefficient's.
+func (hs *HashSequential) hashValues(s string, values []uint) uint {
+	if hs.Width == WidthUint {
+		h := uint(len(s)) * values[0]
+		for i, c := range hs.Coefs {
+			c.Value = values[i+1]
+			h = c.Apply(h, s)
+		}
+		return h
+	}
+	h := uint64(len(s)) * uint64(values[0])
+	for i, c := range hs.Coefs {
+		c.Value = values[i+1]
+		h = c.apply64(h, s)
+	}
+	return uint(hs.Width.wrap(h))
 }
 
 // Increment advances coefficients to try the next hash function. Returns true when exhausted.
@@ -204,29 +315,36 @@ func (phf *HashFinder) SearchSuccessProbability(tableSizeBits int, inputs int, a
 
 // Search finds coefficients that produce unique hashes for all inputs.
 // Returns the number of attempts and an error if no perfect hash was found.
+// A [HashSequential] is evaluated over the [keyMatrix] of the inputs.
 func (phf *HashFinder) Search(hasher Hash, tableSizeBits int, inputs []string) (int, error) {
-	if tableSizeBits <= 0 || tableSizeBits > 32 {
-		return 0, errors.New("zero/negative bits for table size or too large")
-	} else if len(inputs) == 0 {
-		return 0, errors.New("zero inputs")
+	mask, err := phf.init(tableSizeBits, inputs)
+	if err != nil {
+		return 0, err
 	}
-	tblsz := 1 << tableSizeBits
-	phf.hashmap = slices.Grow(phf.hashmap[:0], tblsz)[:tblsz]
 	hashmap := phf.hashmap
-	mask := uint(tblsz) - 1
+	order := phf.searchOrder(inputs)
+	hs, m := phf.sequentialMatrix(hasher, inputs)
 	currentAttempt := 0
 	for {
 		currentAttempt++
 		attemptSuccess := true
-		clear(hashmap)
-		for _, kw := range inputs {
-			h := hasher.Hash(kw) & mask
+		epoch := phf.nextEpoch()
+		for i, idx := range order {
+			var h uint
+			if m != nil {
+				h = uint(m.hash(hs, phf.cols, int(idx))) & mask
+			} else {
+				h = hasher.Hash(inputs[idx]) & mask
+			}
 			tok := hashmap[h]
-			if tok != 0 {
+			if tok == epoch {
 				attemptSuccess = false
+				if !phf.KeepOrder {
+					moveToFront(order, i)
+				}
 				break
 			}
-			hashmap[h] = 1
+			hashmap[h] = epoch
 		}
 		if attemptSuccess {
 			return currentAttempt, nil
@@ -239,6 +357,93 @@ func (phf *HashFinder) Search(hasher Hash, tableSizeBits int, inputs []string) (
 	return currentAttempt, ErrNoCoefficientsFound
 }
 
+// SearchBest is like [HashFinder.Search] but keeps track of the configuration with the
+// fewest colliding inputs. If no perfect hash exists in the search space it returns
+// [ErrNoCoefficientsFound] along with the best configuration found and the inputs that
+// collided under it, which is enough to build a near-perfect [Lookup] with a short overflow list.
+func (phf *HashFinder) SearchBest(hasher Cloner, tableSizeBits int, inputs []string) (SearchResult, error) {
+	mask, err := phf.init(tableSizeBits, inputs)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	hashmap := phf.hashmap
+	hs, m := phf.sequentialMatrix(hasher, inputs)
+	var result SearchResult
+	bestCollisions := len(inputs)
+	for {
+		result.Attempts++
+		collisions := 0
+		epoch := phf.nextEpoch()
+		for k, kw := range inputs {
+			var h uint
+			if m != nil {
+				h = uint(m.hash(hs, phf.cols, k)) & mask
+			} else {
+				h = hasher.Hash(kw) & mask
+			}
+			if hashmap[h] == epoch {
+				collisions++
+				if collisions >= bestCollisions {
+					break // Can't improve on best, reject early.
+				}
+			}
+			hashmap[h] = epoch
+		}
+		if collisions < bestCollisions {
+			bestCollisions = collisions
+			result.Best = hasher.Clone()
+			if collisions == 0 {
+				return result, nil
+			}
+		}
+		cannotContinue := hasher.Increment()
+		if cannotContinue {
+			break
+		}
+	}
+	// First key never collides so Best is always set at this point.
+	result.Collisions = phf.collisions(result.Best, mask, inputs)
+	return result, ErrNoCoefficientsFound
+}
+
+// collisions returns the inputs that land on a slot occupied by an earlier input, in input order.
+func (phf *HashFinder) collisions(hasher Hash, mask uint, inputs []string) (collided []string) {
+	hashmap := phf.hashmap
+	epoch := phf.nextEpoch()
+	for _, kw := range inputs {
+		h := hasher.Hash(kw) & mask
+		if hashmap[h] == epoch {
+			collided = append(collided, kw)
+		}
+		hashmap[h] = epoch
+	}
+	return collided
+}
+
+// nextEpoch starts an attempt over the finder's table and returns the value marking
+// slots occupied during it. Slots marked by earlier attempts hold smaller values.
+func (phf *HashFinder) nextEpoch() uint {
+	phf.epoch++
+	if phf.epoch == 0 {
+		// Wrapped around, stale marks could be mistaken for current ones.
+		clear(phf.hashmap)
+		phf.epoch = 1
+	}
+	return phf.epoch
+}
+
+// init validates search arguments and sizes the finder's table. Returns the table mask.
+func (phf *HashFinder) init(tableSizeBits int, inputs []string) (mask uint, err error) {
+	if tableSizeBits <= 0 || tableSizeBits > 32 {
+		return 0, errors.New("zero/negative bits for table size or too large")
+	} else if len(inputs) == 0 {
+		return 0, errors.New("zero inputs")
+	}
+	tblsz := 1 << tableSizeBits
+	phf.hashmap = slices.Grow(phf.hashmap[:0], tblsz)[:tblsz]
+	return uint(tblsz) - 1, nil
+}
+
 // Apply combines the byte at IndexApplied with h using the coefficient's operation.
 // If IndexApplied is out of bounds for kw (positive index >= len or negative index
 // beyond start), h is returned unchanged and no operation is applied.
@@ -265,6 +470,26 @@ func (coef *Coef) Apply(h uint, kw string) uint {
 	return h
 }
 
+// apply64 is [Coef.Apply] in 64-bit arithmetic regardless of the width of uint.
+func (coef *Coef) apply64(h uint64, kw string) uint64 {
+	b, ok := byteAt(kw, coef.IndexApplied)
+	if !ok {
+		return h
+	}
+	a := uint64(b) * uint64(coef.Value)
+	switch coef.Op {
+	case OpAdd:
+		h += a
+	case OpXor:
+		h ^= a
+	case OpMul:
+		h *= a
+	default:
+		panic("unsupported operation")
+	}
+	return h
+}
+
 func (coef *Coef) config(defaultMax uint) error {
 	coef.init()
 	if coef.MaxValue == 0 {
@@ -299,3 +524,6 @@ func (op Op) String() (s string) {
 	}
 	return s
 }
+
+// valid reports whether op is one of the defined operations.
+func (op Op) valid() bool { return op >= OpAdd && op <= OpMul }
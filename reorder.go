@@ -0,0 +1,70 @@
+package perfect
+
+import "slices"
+
+// searchOrder returns the indices of inputs in the order a search hashes them. Unless
+// KeepOrder is set the keys most likely to collide come first: those with
+// the most near-identical keys, differing from them in a single byte, which hashes
+// reading few bytes struggle to tell apart. Search then moves the key of each collision
+// to the front so that later attempts are rejected after fewer hashes.
+//
+// The order is kept by the finder and reused, along with what was learned from
+// collisions, by later searches over the same inputs.
+func (phf *HashFinder) searchOrder(inputs []string) []int32 {
+	if phf.KeepOrder {
+		phf.orderInputs = phf.orderInputs[:0]
+		phf.order = phf.order[:0]
+		for i := range inputs {
+			phf.order = append(phf.order, int32(i))
+		}
+		return phf.order
+	}
+	if len(phf.order) == len(inputs) && slices.Equal(phf.orderInputs, inputs) {
+		return phf.order
+	}
+	phf.orderInputs = append(phf.orderInputs[:0], inputs...)
+	phf.order = phf.order[:0]
+	for i := range inputs {
+		phf.order = append(phf.order, int32(i))
+	}
+	positions := 0
+	for _, kw := range inputs {
+		positions += len(kw)
+	}
+	near := make(map[uint64]int32, positions)
+	for _, kw := range inputs {
+		for pos := range len(kw) {
+			near[nearKeyHash(kw, pos)]++
+		}
+	}
+	neighbours := make([]int32, len(inputs))
+	for i, kw := range inputs {
+		for pos := range len(kw) {
+			neighbours[i] += near[nearKeyHash(kw, pos)] - 1
+		}
+	}
+	slices.SortStableFunc(phf.order, func(a, b int32) int { return int(neighbours[b] - neighbours[a]) })
+	return phf.order
+}
+
+// nearKeyHash returns a 64-bit FNV-1a hash of kw without its byte at pos, and of pos.
+// Keys of equal hash for a position almost certainly differ only in the byte at it.
+func nearKeyHash(kw string, pos int) uint64 {
+	const prime = 1099511628211
+	h := uint64(14695981039346656037) ^ uint64(pos)
+	h *= prime
+	for i := range len(kw) {
+		if i != pos {
+			h ^= uint64(kw[i])
+			h *= prime
+		}
+	}
+	return h
+}
+
+// moveToFront moves order[i] to the front, shifting the indices before it back by one.
+func moveToFront(order []int32, i int) {
+	idx := order[i]
+	copy(order[1:i+1], order[:i])
+	order[0] = idx
+}
@@ -0,0 +1,54 @@
+package perfect
+
+import "math"
+
+// Score describes how a hash distributes inputs over a table. See [HashFinder.Score].
+type Score struct {
+	Collisions int // Inputs that land on a slot occupied by an earlier input. Zero for a perfect hash.
+	MaxLoad    int // Most inputs landing on a single slot.
+	UsedSlots  int // Slots holding at least one input.
+	TableSize  int // Number of slots in the table.
+	// Loads is the load histogram: Loads[k] is the number of slots holding k inputs,
+	// for k up to MaxLoad. Loads[0] is the number of empty slots.
+	Loads []int
+	// RandomCollisions is the expected Collisions of a uniformly random hash of the
+	// same number of inputs and table size. Hashes scoring above it spread worse than chance.
+	RandomCollisions float64
+}
+
+// Perfect reports whether every input landed on a distinct slot.
+func (s Score) Perfect() bool { return s.Collisions == 0 }
+
+// Score hashes all inputs into a table of 1<<tableSizeBits slots with the current
+// configuration of hasher and returns how they are distributed. Unlike [HashFinder.Search],
+// which rejects a configuration at its first collision, every input is hashed so the score
+// tells how close a configuration is to perfect. It is the building block for custom
+// search strategies and for diagnosing why a search fails. hasher is not incremented.
+func (phf *HashFinder) Score(hasher Hash, tableSizeBits int, inputs []string) (Score, error) {
+	mask, err := phf.init(tableSizeBits, inputs)
+	if err != nil {
+		return Score{}, err
+	}
+	hashmap := phf.hashmap
+	clear(hashmap)
+	score := Score{TableSize: len(hashmap)}
+	for _, kw := range inputs {
+		h := hasher.Hash(kw) & mask
+		if hashmap[h] != 0 {
+			score.Collisions++
+		} else {
+			score.UsedSlots++
+		}
+		hashmap[h]++
+		score.MaxLoad = max(score.MaxLoad, int(hashmap[h]))
+	}
+	score.Loads = make([]int, score.MaxLoad+1)
+	for _, load := range hashmap {
+		score.Loads[load]++
+	}
+	clear(hashmap) // Counts must not be mistaken for epochs of later searches.
+	// Expected number of occupied slots is m*(1-(1-1/m)^n), the rest of the inputs collide.
+	n, m := float64(len(inputs)), float64(len(hashmap))
+	score.RandomCollisions = n - m*(1-math.Pow(1-1/m, n))
+	return score, nil
+}
@@ -0,0 +1,43 @@
+package perfect
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+)
+
+func ExampleHashFinder_Score() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	hasher := &HashSequential{
+		Coefs: []Coef{{IndexApplied: 0, Op: OpXor}, {IndexApplied: 1, Op: OpXor}, {IndexApplied: -1, Op: OpAdd}},
+	}
+	err := hasher.ConfigCoefs(16)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	// Score the starting configuration, then the one found by a search.
+	score, err := phf.Score(hasher, 6, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("initial: %d collisions (random hash %.1f), max load %d, loads %v\n",
+		score.Collisions, score.RandomCollisions, score.MaxLoad, score.Loads)
+	_, err = phf.Search(hasher, 6, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	score, err = phf.Score(hasher, 6, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("found: perfect=%v, %d of %d slots used\n", score.Perfect(), score.UsedSlots, score.TableSize)
+	// Output:
+	// initial: 7 collisions (random hash 4.2), max load 3, loads [46 12 5 1]
+	// found: perfect=true, 25 of 64 slots used
+}
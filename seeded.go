@@ -0,0 +1,151 @@
+package perfect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// HashSeeded hashes the full key with a fast general-purpose function and a seed,
+// and Increment simply advances the seed. Unlike the sampled hash families it can
+// tell apart any two distinct keys, so it is a good fallback when no position subset works.
+//
+// Different seeds behave like independent random functions, so each attempt of
+// [HashFinder.Search] succeeds with probability [HashFinder.CollisionFreeProbability]
+// and the chance of success over the whole search is given by [HashFinder.SearchSuccessProbability]
+// with [HashSeeded.SearchSpace] attempts.
+type HashSeeded struct {
+	Func    SeededFunc
+	Seed    uint64 // Current seed.
+	MaxSeed uint64 // Seed at which the search is exhausted.
+}
+
+// SeededFunc is the general-purpose hash function used by a [HashSeeded].
+type SeededFunc int
+
+const (
+	seededUndefined SeededFunc = iota
+	SeededFNV1a                // FNV-1a over every byte, seed mixed into the offset basis.
+	SeededWyhash               // wyhash-like, 8 bytes at a time with multiply-xorshift mixing.
+)
+
+func (fn SeededFunc) String() (s string) {
+	switch fn {
+	case SeededFNV1a:
+		s = "fnv1a"
+	case SeededWyhash:
+		s = "wyhash"
+	default:
+		s = "<unknownfunc>"
+	}
+	return s
+}
+
+const (
+	fnvOffset64 = 0xcbf29ce484222325
+	fnvPrime64  = 0x100000001b3
+	wyP0        = 0xa0761d6478bd642f
+	wyP1        = 0xe7037ed1a0b428db
+)
+
+// ConfigSeed validates hs's configuration. An unset Func defaults to [SeededFNV1a].
+func (hs *HashSeeded) ConfigSeed() error {
+	if hs.Func == seededUndefined {
+		hs.Func = SeededFNV1a
+	}
+	if hs.Func != SeededFNV1a && hs.Func != SeededWyhash {
+		return errors.New("unknown seeded hash function")
+	} else if hs.Seed >= hs.MaxSeed {
+		return errors.New("seed must be less than MaxSeed")
+	}
+	return nil
+}
+
+// Hash computes the hash value for the given string.
+func (hs *HashSeeded) Hash(dataToHash string) uint {
+	var h uint64
+	switch hs.Func {
+	case SeededFNV1a:
+		h = fnv1a(dataToHash, hs.Seed)
+	case SeededWyhash:
+		h = wyhash(dataToHash, hs.Seed)
+	default:
+		panic("unsupported seeded function")
+	}
+	return uint(h ^ h>>32)
+}
+
+// Increment advances the seed. Returns true when exhausted.
+func (hs *HashSeeded) Increment() (done bool) {
+	hs.Seed++
+	return hs.Seed >= hs.MaxSeed
+}
+
+// Clone returns a copy of hs as a [Hash].
+func (hs *HashSeeded) Clone() Hash {
+	clone := *hs
+	return &clone
+}
+
+// SearchSpace returns the number of seeds that will be tried in an exhaustive search.
+func (hs *HashSeeded) SearchSpace() uint64 {
+	if hs.Seed >= hs.MaxSeed {
+		return 0
+	}
+	return hs.MaxSeed - hs.Seed
+}
+
+func fnv1a(s string, seed uint64) uint64 {
+	h := fnvOffset64 ^ seed*fnvPrime64
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime64
+	}
+	return h
+}
+
+func wyhash(s string, seed uint64) uint64 {
+	h := seed ^ wyP0 ^ uint64(len(s))*wyP1
+	for ; len(s) > 8; s = s[8:] {
+		h = wymix(h ^ load64(s))
+	}
+	var tail uint64
+	for i := 0; i < len(s); i++ {
+		tail |= uint64(s[i]) << (8 * i)
+	}
+	return wymix(wymix(h ^ tail))
+}
+
+func wymix(h uint64) uint64 {
+	h *= wyP1
+	h ^= h >> 32
+	h *= wyP0
+	h ^= h >> 29
+	return h
+}
+
+// WriteGoFunc writes hs as a Go function named name. See [GoHasher].
+func (hs *HashSeeded) WriteGoFunc(w io.Writer, name string) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "func %s(s string) uint {\n", name)
+	switch hs.Func {
+	case SeededFNV1a:
+		fmt.Fprintf(&buf, "h := uint64(%#x)\n", uint64(fnvOffset64^hs.Seed*fnvPrime64))
+		fmt.Fprintf(&buf, "for i := 0; i < len(s); i++ {\nh ^= uint64(s[i])\nh *= %#x\n}\n", uint64(fnvPrime64))
+	case SeededWyhash:
+		fmt.Fprintf(&buf, "h := uint64(%#x) ^ uint64(len(s))*%#x\n", hs.Seed^wyP0, uint64(wyP1))
+		fmt.Fprintf(&buf, "for ; len(s) > 8; s = s[8:] {\n")
+		buf.WriteString("h ^= uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 | uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56\n")
+		fmt.Fprintf(&buf, "h = %sMix(h)\n}\n", name)
+		buf.WriteString("var tail uint64\nfor i := 0; i < len(s); i++ {\ntail |= uint64(s[i]) << (8 * i)\n}\n")
+		fmt.Fprintf(&buf, "h = %sMix(%sMix(h ^ tail))\n", name, name)
+	default:
+		return errors.New("unknown seeded hash function")
+	}
+	buf.WriteString("return uint(h ^ h>>32)\n}\n")
+	if hs.Func == SeededWyhash {
+		fmt.Fprintf(&buf, "\nfunc %sMix(h uint64) uint64 {\nh *= %#x\nh ^= h >> 32\nh *= %#x\nh ^= h >> 29\nreturn h\n}\n", name, uint64(wyP1), uint64(wyP0))
+	}
+	return writeGoSource(w, buf.Bytes())
+}
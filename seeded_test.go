@@ -0,0 +1,36 @@
+package perfect
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+)
+
+func ExampleHashSeeded() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	hasher := &HashSeeded{Func: SeededWyhash, MaxSeed: 1000}
+	err := hasher.ConfigSeed()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	const tablesizebits = 6
+	prob, err := phf.SearchSuccessProbability(tablesizebits, len(keywords), hasher.SearchSpace())
+	if err != nil {
+		log.Fatalln(err)
+	}
+	fmt.Printf("seeded search for Go's %d keywords has %.2f%% success probability\n", len(keywords), 100*prob)
+	attempts, err := phf.Search(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err, "after", attempts, "attempts")
+	}
+	fmt.Printf("found seed %d after %d attempts\n", hasher.Seed, attempts)
+	// Output:
+	// seeded search for Go's 25 keywords has 98.73% success probability
+	// found seed 12 after 13 attempts
+}
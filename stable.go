@@ -0,0 +1,122 @@
+package perfect
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SearchStable is like [HashFinder.SearchBest] restricted to perfect hashes, but prefers
+// the hash which keeps the most keys at the slot prior assigns them, such as the
+// [Lookup.Slots] of a previous generation. The search stops early once every key of prior
+// still in inputs keeps its slot, otherwise the whole search space is tried, so
+// regenerated tables change as little as possible. Keys in prior but not in inputs are ignored.
+// Returns [ErrNoCoefficientsFound] if no perfect hash was found.
+func (phf *HashFinder) SearchStable(hasher Cloner, tableSizeBits int, inputs []string, prior map[string]int) (SearchResult, error) {
+	mask, err := phf.init(tableSizeBits, inputs)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	wantSlots := make([]int, len(inputs))
+	for i, kw := range inputs {
+		slot, ok := prior[kw]
+		if !ok {
+			slot = -1 // New key, any slot will do.
+		}
+		wantSlots[i] = slot
+	}
+	hashmap := phf.hashmap
+	hs, m := phf.sequentialMatrix(hasher, inputs)
+	var result SearchResult
+	bestMoved := len(inputs) + 1
+	for {
+		result.Attempts++
+		moved := 0
+		perfect := true
+		epoch := phf.nextEpoch()
+		for i, kw := range inputs {
+			var h uint
+			if m != nil {
+				h = uint(m.hash(hs, phf.cols, i)) & mask
+			} else {
+				h = hasher.Hash(kw) & mask
+			}
+			if hashmap[h] == epoch {
+				perfect = false
+				break
+			}
+			hashmap[h] = epoch
+			if wantSlots[i] >= 0 && uint(wantSlots[i]) != h {
+				moved++
+				if moved >= bestMoved {
+					perfect = false // Can't improve on best, reject early.
+					break
+				}
+			}
+		}
+		if perfect {
+			bestMoved = moved
+			result.Best = hasher.Clone()
+			if moved == 0 {
+				return result, nil
+			}
+		}
+		cannotContinue := hasher.Increment()
+		if cannotContinue {
+			break
+		}
+	}
+	if result.Best == nil {
+		return result, ErrNoCoefficientsFound
+	}
+	return result, nil
+}
+
+// Slots returns the slot of every key which has one. Keys in the overflow list are omitted.
+func (l *Lookup) Slots() map[string]int {
+	slots := make(map[string]int, len(l.keys))
+	for i, slot := range l.keySlots() {
+		if slot >= 0 {
+			slots[l.keys[i]] = slot
+		}
+	}
+	return slots
+}
+
+// SlotChange is a key whose slot differs between two slot assignments.
+type SlotChange struct {
+	Key      string
+	Old, New int // Slot of Key, or -1 if it had none.
+}
+
+func (sc SlotChange) String() string {
+	slot := func(s int) string {
+		if s < 0 {
+			return "none"
+		}
+		return fmt.Sprint(s)
+	}
+	return fmt.Sprintf("%q: %s -> %s", sc.Key, slot(sc.Old), slot(sc.New))
+}
+
+// DiffSlots returns the keys whose slot differs between the old and new slot assignments,
+// including keys added or removed, sorted by key.
+func DiffSlots(old, new map[string]int) []SlotChange {
+	var changes []SlotChange
+	for kw, oldSlot := range old {
+		newSlot, ok := new[kw]
+		if !ok {
+			newSlot = -1
+		}
+		if newSlot != oldSlot {
+			changes = append(changes, SlotChange{Key: kw, Old: oldSlot, New: newSlot})
+		}
+	}
+	for kw, newSlot := range new {
+		if _, ok := old[kw]; !ok {
+			changes = append(changes, SlotChange{Key: kw, Old: -1, New: newSlot})
+		}
+	}
+	slices.SortFunc(changes, func(a, b SlotChange) int { return strings.Compare(a.Key, b.Key) })
+	return changes
+}
@@ -0,0 +1,78 @@
+package perfect
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+	"slices"
+)
+
+func ExampleHashFinder_SearchStable() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	newHasher := func() *HashSequential {
+		hasher := &HashSequential{
+			LenCoef: Coef{IndexApplied: 0, Op: OpAdd},
+			Coefs: []Coef{
+				{IndexApplied: 0, Op: OpXor},
+				{IndexApplied: 1, Op: OpXor},
+				{IndexApplied: -1, Op: OpAdd},
+			},
+		}
+		err := hasher.ConfigCoefs(16)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		return hasher
+	}
+	const tablesizebits = 6
+	var phf HashFinder
+	hasher := newHasher()
+	_, err := phf.Search(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	previous, err := NewLookup(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// Regenerate without defer and with nil.
+	keywords = slices.DeleteFunc(slices.Clone(keywords), func(kw string) bool { return kw == "defer" })
+	keywords = append(keywords, "nil")
+	for _, stable := range []bool{false, true} {
+		var found Hash = newHasher()
+		if stable {
+			result, err := phf.SearchStable(newHasher(), tablesizebits, keywords, previous.Slots())
+			if err != nil {
+				log.Fatalln(err)
+			}
+			found = result.Best
+		} else {
+			_, err = phf.Search(found, tablesizebits, keywords)
+			if err != nil {
+				log.Fatalln(err)
+			}
+		}
+		regenerated, err := NewLookup(found, tablesizebits, keywords)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		diff := DiffSlots(previous.Slots(), regenerated.Slots())
+		fmt.Printf("stable=%v: %d slot changes\n", stable, len(diff))
+		if stable {
+			for _, change := range diff {
+				fmt.Println(change)
+			}
+		}
+	}
+	// Output:
+	// stable=false: 26 slot changes
+	// stable=true: 2 slot changes
+	// "defer": 11 -> none
+	// "nil": none -> 11
+}
@@ -0,0 +1,218 @@
+package perfect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Strategy is a form of generated recognizer for a static key set.
+type Strategy int
+
+const (
+	strategyUndefined Strategy = iota
+	StrategyHash               // Perfect hash lookup, generated by [Lookup.WriteGo] and friends.
+	StrategySwitch             // A single `switch s` over all keys.
+	StrategyLenSwitch          // A `switch len(s)` followed by a `switch s[0]` and comparisons.
+)
+
+func (st Strategy) String() (s string) {
+	switch st {
+	case StrategyHash:
+		s = "hash"
+	case StrategySwitch:
+		s = "switch"
+	case StrategyLenSwitch:
+		s = "lenswitch"
+	default:
+		s = "<unknownstrategy>"
+	}
+	return s
+}
+
+// ChooseStrategy returns the recognizer expected to be fastest for keys. It is a heuristic:
+// tiny key sets are best served by a plain switch, which the compiler turns into a binary
+// search, and key sets where few keys share a length and first byte are resolved by a
+// length and first byte switch with jump tables and a single comparison. Larger sets
+// are best served by a perfect hash. Verify the choice with [WriteGoBenchmark].
+func ChooseStrategy(keys []string) Strategy {
+	const maxSwitchKeys = 8
+	if len(keys) <= maxSwitchKeys {
+		return StrategySwitch
+	}
+	type lenByte struct {
+		length int
+		first  byte
+	}
+	groups := make(map[lenByte]int)
+	maxGroup := 0
+	for _, kw := range keys {
+		var k lenByte
+		k.length = len(kw)
+		if len(kw) > 0 {
+			k.first = kw[0]
+		}
+		groups[k]++
+		maxGroup = max(maxGroup, groups[k])
+	}
+	if maxGroup == 1 || 2*len(groups) >= len(keys) && maxGroup <= 3 {
+		return StrategyLenSwitch
+	}
+	return StrategyHash
+}
+
+// WriteGoSwitch writes a Go source file to w declaring a function `func Name(s string) int`
+// implemented as a switch of the given strategy, which returns the index of s in keys
+// or -1 if s is not a key. Strategy must be [StrategySwitch] or [StrategyLenSwitch].
+func WriteGoSwitch(w io.Writer, cfg GoConfig, strategy Strategy, keys []string) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	} else if len(keys) == 0 {
+		return errors.New("zero inputs")
+	}
+	seen := make(map[string]struct{}, len(keys))
+	for _, kw := range keys {
+		if _, dup := seen[kw]; dup {
+			return errors.New("duplicate key " + kw)
+		}
+		seen[kw] = struct{}{}
+	}
+	name := cfg.Name
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, genHeader+"package %s\n\n", cfg.Package)
+	fmt.Fprintf(&buf, "// %s returns the index of s in the key set or -1 if s is not a key.\n", name)
+	fmt.Fprintf(&buf, "func %s(s string) int {\n", name)
+	switch strategy {
+	case StrategySwitch:
+		buf.WriteString("switch s {\n")
+		for i, kw := range keys {
+			fmt.Fprintf(&buf, "case %q:\nreturn %d\n", kw, i)
+		}
+		buf.WriteString("}\n")
+	case StrategyLenSwitch:
+		writeGoLenSwitch(&buf, keys)
+	default:
+		return fmt.Errorf("unsupported switch strategy %s", strategy)
+	}
+	buf.WriteString("return -1\n}\n")
+	return writeGoSource(w, buf.Bytes())
+}
+
+func writeGoLenSwitch(buf *bytes.Buffer, keys []string) {
+	idxs := make([]int, len(keys))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	// Sort by length then first byte, keeping key order within groups.
+	slices.SortStableFunc(idxs, func(a, b int) int {
+		ka, kb := keys[a], keys[b]
+		if len(ka) != len(kb) || len(ka) == 0 {
+			return len(ka) - len(kb)
+		}
+		return int(ka[0]) - int(kb[0])
+	})
+	buf.WriteString("switch len(s) {\n")
+	for i := 0; i < len(idxs); {
+		length := len(keys[idxs[i]])
+		fmt.Fprintf(buf, "case %d:\n", length)
+		if length == 0 {
+			fmt.Fprintf(buf, "return %d\n", idxs[i])
+			i++
+			continue
+		}
+		buf.WriteString("switch s[0] {\n")
+		for i < len(idxs) && len(keys[idxs[i]]) == length {
+			first := keys[idxs[i]][0]
+			fmt.Fprintf(buf, "case %s:\n", goByteLit(first))
+			for ; i < len(idxs) && len(keys[idxs[i]]) == length && keys[idxs[i]][0] == first; i++ {
+				fmt.Fprintf(buf, "if s == %q {\nreturn %d\n}\n", keys[idxs[i]], idxs[i])
+			}
+		}
+		buf.WriteString("}\n")
+	}
+	buf.WriteString("}\n")
+}
+
+// goByteLit returns a Go literal for b, a character literal for printable ASCII.
+func goByteLit(b byte) string {
+	if b < utf8.RuneSelf && unicode.IsPrint(rune(b)) {
+		return fmt.Sprintf("%q", rune(b))
+	}
+	return fmt.Sprintf("%#x", b)
+}
+
+// WriteGoBenchmark writes a Go test file to w with a benchmark comparing generated
+// recognizers of keys, such as a perfect hash lookup and a switch, each a function
+// `func(string) int` named in funcs. Inputs alternate between keys and near-miss non-keys.
+// The benchmark is named after cfg.Name.
+func WriteGoBenchmark(w io.Writer, cfg GoConfig, keys []string, funcs ...string) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	} else if len(funcs) == 0 {
+		return errors.New("no functions to benchmark")
+	} else if len(keys) == 0 {
+		return errors.New("zero inputs")
+	}
+	name := cfg.Name
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, genHeader+"package %s\n\nimport \"testing\"\n\n", cfg.Package)
+	fmt.Fprintf(&buf, "func Benchmark%s(b *testing.B) {\n", exportedName(name))
+	buf.WriteString("for _, bb := range []struct {\nname string\nfn func(string) int\n}{\n")
+	for _, fn := range funcs {
+		fmt.Fprintf(&buf, "{%q, %s},\n", fn, fn)
+	}
+	buf.WriteString("} {\nb.Run(bb.name, func(b *testing.B) {\n")
+	fmt.Fprintf(&buf, "inputs := %sBenchInputs[:]\n", name)
+	buf.WriteString("for i := 0; i < b.N; i++ {\nbb.fn(inputs[i%len(inputs)])\n}\n})\n}\n}\n\n")
+	writeGoBenchInputs(&buf, name+"BenchInputs", keys)
+	return writeGoSource(w, buf.Bytes())
+}
+
+// writeGoBenchInputs writes an array of benchmark inputs alternating between keys and near-miss non-keys.
+func writeGoBenchInputs(buf *bytes.Buffer, name string, keys []string) {
+	misses := nearMisses(keys)
+	fmt.Fprintf(buf, "var %s = [...]string{\n", name)
+	for i, kw := range keys {
+		fmt.Fprintf(buf, "%q,", kw)
+		if len(misses) > 0 {
+			fmt.Fprintf(buf, " %q,", misses[i%len(misses)])
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n\n")
+}
+
+// nearMisses returns strings which are not keys but are close to keys: keys with their
+// last byte changed and keys missing their last byte.
+func nearMisses(keys []string) []string {
+	isKey := make(map[string]bool, len(keys))
+	for _, kw := range keys {
+		isKey[kw] = true
+	}
+	var misses []string
+	add := func(s string) {
+		if !isKey[s] {
+			isKey[s] = true // Avoid duplicates.
+			misses = append(misses, s)
+		}
+	}
+	for _, kw := range keys {
+		if len(kw) == 0 {
+			continue
+		}
+		last := kw[len(kw)-1]
+		add(kw[:len(kw)-1] + string([]byte{last ^ 1}))
+		add(kw[:len(kw)-1])
+	}
+	return misses
+}
+
+// exportedName returns name with its first letter in upper case.
+func exportedName(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -0,0 +1,61 @@
+package perfect
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+func ExampleWriteGoSwitch() {
+	keywords := []string{"if", "in", "do", "for", "func", "var"}
+	strategy := ChooseStrategy(keywords)
+	fmt.Println("strategy:", strategy)
+	err := WriteGoSwitch(os.Stdout, GoConfig{Package: "lexer", Name: "keyword"}, StrategyLenSwitch, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// Output:
+	// strategy: switch
+	// // Code generated by github.com/soypat/perfect. DO NOT EDIT.
+	//
+	// package lexer
+	//
+	// // keyword returns the index of s in the key set or -1 if s is not a key.
+	// func keyword(s string) int {
+	// 	switch len(s) {
+	// 	case 2:
+	// 		switch s[0] {
+	// 		case 'd':
+	// 			if s == "do" {
+	// 				return 2
+	// 			}
+	// 		case 'i':
+	// 			if s == "if" {
+	// 				return 0
+	// 			}
+	// 			if s == "in" {
+	// 				return 1
+	// 			}
+	// 		}
+	// 	case 3:
+	// 		switch s[0] {
+	// 		case 'f':
+	// 			if s == "for" {
+	// 				return 3
+	// 			}
+	// 		case 'v':
+	// 			if s == "var" {
+	// 				return 5
+	// 			}
+	// 		}
+	// 	case 4:
+	// 		switch s[0] {
+	// 		case 'f':
+	// 			if s == "func" {
+	// 				return 4
+	// 			}
+	// 		}
+	// 	}
+	// 	return -1
+	// }
+}
@@ -0,0 +1,233 @@
+package perfect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"strconv"
+)
+
+// TwoLevel is a two-level (FKS-style) perfect hash for key sets too large for a single
+// [HashSequential] search. The First hash distributes keys into 2^BucketBits buckets
+// and each bucket with more than one key gets its own second-level hash over a table
+// sized to the square of its key count, which makes finding a collision-free hash per bucket easy.
+//
+// Second-level hashes share the operations and indices of the Second template and
+// differ only in their coefficient values, which are searched for each bucket independently.
+type TwoLevel struct {
+	// First hash distributing keys into buckets. Configured by the user and incremented
+	// by [HashFinder.SearchTwoLevel] until the total table size is within bounds.
+	First HashSequential
+	// Second is the configured template for second-level hashes.
+	Second HashSequential
+	// BucketBits sets the number of first-level buckets. A good starting point is log2 of the number of keys.
+	BucketBits int
+	// MaxSlotsPerKey bounds total table memory to MaxSlotsPerKey*len(keys) slots. Defaults to 4.
+	MaxSlotsPerKey int
+
+	keys    []string
+	slots   []int32 // Key index plus one per slot, zero if slot is empty.
+	buckets []twoLevelBucket
+	values  []uint // Second-level coefficient values, len(Second.Coefs)+1 per bucket starting with length coefficient.
+}
+
+type twoLevelBucket struct {
+	offset uint32 // Start of bucket's table in slots.
+	mask   uint32 // Bucket's table size minus one.
+}
+
+// SearchTwoLevel searches a two-level perfect hash for inputs as configured by tl.
+// Buckets for which no second-level hash is found over n² slots get up to 8 times
+// as many slots before the first-level hash is incremented and the search restarted.
+// It returns the total number of first and second-level attempts.
+func (phf *HashFinder) SearchTwoLevel(tl *TwoLevel, inputs []string) (int, error) {
+	if tl.BucketBits <= 0 || tl.BucketBits > 32 {
+		return 0, errors.New("zero/negative bucket bits or too large")
+	} else if len(inputs) == 0 {
+		return 0, errors.New("zero inputs")
+	} else if len(tl.Second.Coefs) == 0 {
+		return 0, errors.New("second-level template has no coefficients")
+	}
+	maxSlotsPerKey := tl.MaxSlotsPerKey
+	if maxSlotsPerKey <= 0 {
+		maxSlotsPerKey = 4
+	}
+	maxSlots := maxSlotsPerKey * len(inputs)
+	nbuckets := 1 << tl.BucketBits
+	bucketMask := uint(nbuckets) - 1
+	seen := make(map[string]struct{}, len(inputs))
+	for _, kw := range inputs {
+		if _, dup := seen[kw]; dup {
+			return 0, errors.New("duplicate key " + kw)
+		}
+		seen[kw] = struct{}{}
+	}
+	bucketKeys := make([][]string, nbuckets)
+	attempts := 0
+	for {
+		attempts++
+		for i := range bucketKeys {
+			bucketKeys[i] = bucketKeys[i][:0]
+		}
+		for _, kw := range inputs {
+			b := tl.First.Hash(kw) & bucketMask
+			bucketKeys[b] = append(bucketKeys[b], kw)
+		}
+		totalSlots := 0
+		for _, keys := range bucketKeys {
+			totalSlots += 1 << twoLevelBucketBits(len(keys))
+		}
+		if totalSlots <= maxSlots {
+			a, err := phf.searchSecondLevels(tl, bucketKeys, maxSlots)
+			attempts += a
+			if err == nil {
+				break
+			} else if !errors.Is(err, ErrNoCoefficientsFound) {
+				return attempts, err
+			}
+		}
+		if tl.First.Increment() {
+			return attempts, ErrNoCoefficientsFound
+		}
+	}
+	// Second-level hashes found, fill in the slot table.
+	tl.keys = inputs
+	for i, kw := range inputs {
+		tl.slots[tl.Hash(kw)] = int32(i) + 1
+	}
+	return attempts, nil
+}
+
+// searchSecondLevels searches a second-level hash for every bucket. Sets
+// the bucket table layout and values of tl. Bucket tables are grown past
+// the n² slots if no hash is found for them, up to a total of maxSlots
+// which must hold the n² slots of every bucket.
+func (phf *HashFinder) searchSecondLevels(tl *TwoLevel, bucketKeys [][]string, maxSlots int) (int, error) {
+	const maxExtraBits = 3
+	tl.buckets = tl.buckets[:0]
+	tl.values = tl.values[:0]
+	second := tl.Second.Clone().(*HashSequential)
+	attempts := 0
+	offset := 0
+	// reserved is the minimum number of slots of the buckets after the current one,
+	// which growing the current bucket must leave room for.
+	reserved := 0
+	for _, keys := range bucketKeys {
+		reserved += 1 << twoLevelBucketBits(len(keys))
+	}
+	for _, keys := range bucketKeys {
+		tblbits := twoLevelBucketBits(len(keys))
+		reserved -= 1 << tblbits
+		err := second.ConfigCoefs(0)
+		if err != nil {
+			return attempts, fmt.Errorf("second-level template not configured: %w", err)
+		}
+		if len(keys) > 1 {
+			minbits := tblbits
+			for {
+				a, err := phf.Search(second, tblbits, keys)
+				attempts += a
+				if err == nil {
+					break
+				} else if !errors.Is(err, ErrNoCoefficientsFound) {
+					return attempts, err
+				}
+				tblbits++
+				if tblbits > minbits+maxExtraBits || offset+1<<tblbits+reserved > maxSlots {
+					return attempts, err
+				}
+				second.ConfigCoefs(0)
+			}
+		}
+		tl.buckets = append(tl.buckets, twoLevelBucket{offset: uint32(offset), mask: 1<<tblbits - 1})
+		tl.values = append(tl.values, second.LenCoef.Value)
+		for _, c := range second.Coefs {
+			tl.values = append(tl.values, c.Value)
+		}
+		offset += 1 << tblbits
+	}
+	tl.slots = tl.slots[:0]
+	tl.slots = append(tl.slots, make([]int32, offset)...)
+	return attempts, nil
+}
+
+// twoLevelBucketBits returns the table size bits for a bucket with n keys, at least n² slots.
+func twoLevelBucketBits(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	return bits.Len(uint(n*n - 1))
+}
+
+// Hash returns the slot of s in the two-level table, which is unique for every key.
+// Must be called after a successful [HashFinder.SearchTwoLevel].
+func (tl *TwoLevel) Hash(s string) uint {
+	b := tl.First.Hash(s) & (uint(len(tl.buckets)) - 1)
+	bucket := tl.buckets[b]
+	if bucket.mask == 0 {
+		return uint(bucket.offset)
+	}
+	stride := len(tl.Second.Coefs) + 1
+	h := tl.Second.hashValues(s, tl.values[int(b)*stride:int(b+1)*stride])
+	return uint(bucket.offset) + h&uint(bucket.mask)
+}
+
+// TableSize returns the total number of slots used by the second-level tables.
+func (tl *TwoLevel) TableSize() int { return len(tl.slots) }
+
+// Find returns the index of s in the keys tl was searched with.
+// If s is not a key then ok is false.
+func (tl *TwoLevel) Find(s string) (index int, ok bool) {
+	if i := tl.slots[tl.Hash(s)]; i != 0 && tl.keys[i-1] == s {
+		return int(i - 1), true
+	}
+	return -1, false
+}
+
+// WriteGo writes a Go source file to w declaring a function `func Name(s string) int`
+// which returns the index of s in the keys tl was searched with, or -1 if s is not a key.
+func (tl *TwoLevel) WriteGo(w io.Writer, cfg GoConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	} else if len(tl.slots) == 0 {
+		return errors.New("two-level hash not searched")
+	}
+	name := cfg.Name
+	nvalues := len(tl.Second.Coefs) + 1
+	width := tl.Second.Width
+	typ := width.String()
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, genHeader+"package %s\n\n", cfg.Package)
+	fmt.Fprintf(&buf, "// %s returns the index of s in %sKeys or -1 if s is not a key.\n", name, name)
+	fmt.Fprintf(&buf, "func %s(s string) int {\n", name)
+	fmt.Fprintf(&buf, "b := &%sBuckets[%sHash(s)&%d]\n", name, name, len(tl.buckets)-1)
+	fmt.Fprintf(&buf, "h := %s(len(s)) * b.v[0]\n", typ)
+	for i, c := range tl.Second.Coefs {
+		writeGoCoef(&buf, c, typ, "b.v["+strconv.Itoa(i+1)+"]")
+	}
+	fmt.Fprintf(&buf, "i := %sSlots[b.off+uint32(h)&b.mask]\n", name)
+	fmt.Fprintf(&buf, "if i != 0 && %sKeys[i-1] == s {\nreturn int(i - 1)\n}\n", name)
+	buf.WriteString("return -1\n}\n\n")
+
+	fmt.Fprintf(&buf, "var %sBuckets = [%d]struct {\noff, mask uint32\nv [%d]%s\n}{\n", name, len(tl.buckets), nvalues, typ)
+	for b, bucket := range tl.buckets {
+		fmt.Fprintf(&buf, "{%d, %d, [%d]%s{", bucket.offset, bucket.mask, nvalues, typ)
+		for i, v := range tl.values[b*nvalues : (b+1)*nvalues] {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(strconv.FormatUint(width.wrap(uint64(v)), 10))
+		}
+		buf.WriteString("}},\n")
+	}
+	buf.WriteString("}\n\n")
+	writeGoKeys(&buf, name+"Keys", tl.keys)
+	writeGoSlots(&buf, name+"Slots", tl.slots, len(tl.keys))
+	err := tl.First.WriteGoFunc(&buf, name+"Hash")
+	if err != nil {
+		return err
+	}
+	return writeGoSource(w, buf.Bytes())
+}
@@ -0,0 +1,128 @@
+package perfect
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+	"strings"
+)
+
+func ExampleHashFinder_SearchTwoLevel() {
+	// Go's keywords and predeclared identifiers.
+	var tokens []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			tokens = append(tokens, tok.String())
+		}
+	}
+	tokens = append(tokens,
+		"any", "bool", "byte", "comparable", "complex64", "complex128", "error",
+		"float32", "float64", "int", "int8", "int16", "int32", "int64", "rune",
+		"string", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+		"true", "false", "iota", "nil", "append", "cap", "clear", "close",
+		"complex", "copy", "delete", "imag", "len", "make", "max", "min",
+		"new", "panic", "print", "println", "real", "recover",
+	)
+	tl := &TwoLevel{
+		First: HashSequential{
+			Coefs: []Coef{
+				{IndexApplied: 0, Op: OpAdd},
+				{IndexApplied: -1, Op: OpMul},
+			},
+		},
+		Second: HashSequential{
+			Coefs: []Coef{
+				{IndexApplied: 0, Op: OpXor},
+				{IndexApplied: 1, Op: OpAdd},
+				{IndexApplied: -1, Op: OpXor},
+			},
+		},
+		BucketBits: 6,
+	}
+	err := tl.First.ConfigCoefs(64)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	err = tl.Second.ConfigCoefs(16)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	attempts, err := phf.SearchTwoLevel(tl, tokens)
+	if err != nil {
+		log.Fatalln(err, "after", attempts, "attempts")
+	}
+	fmt.Printf("two-level hash for %d tokens found after %d attempts using %d slots\n", len(tokens), attempts, tl.TableSize())
+	for _, s := range []string{"fallthrough", "complex128", "iota", "foo"} {
+		idx, ok := tl.Find(s)
+		fmt.Println(s, idx, ok)
+	}
+	// Output:
+	// two-level hash for 69 tokens found after 162030 attempts using 252 slots
+	// fallthrough 8 true
+	// complex128 30 true
+	// iota 49 true
+	// foo -1 false
+}
+
+func ExampleTwoLevel_width32() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	// Second-level hashes in uint32 arithmetic generate the same code on every platform.
+	tl := &TwoLevel{
+		First: HashSequential{
+			Coefs: []Coef{{IndexApplied: 0, Op: OpAdd}, {IndexApplied: -1, Op: OpMul}},
+			Width: Width32,
+		},
+		Second: HashSequential{
+			Coefs: []Coef{{IndexApplied: 0, Op: OpXor}, {IndexApplied: 1, Op: OpAdd}, {IndexApplied: -1, Op: OpXor}},
+			Width: Width32,
+		},
+		BucketBits: 4,
+	}
+	err := tl.First.ConfigCoefs(64)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	err = tl.Second.ConfigCoefs(16)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	_, err = phf.SearchTwoLevel(tl, keywords)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	found := 0
+	for i, kw := range keywords {
+		if idx, ok := tl.Find(kw); ok && idx == i {
+			found++
+		}
+	}
+	fmt.Println("found", found, "of", len(keywords), "keywords")
+	var buf strings.Builder
+	err = tl.WriteGo(&buf, GoConfig{Package: "keywords", Name: "keyword"})
+	if err != nil {
+		log.Fatalln(err)
+	}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "h ") || strings.HasPrefix(line, "v ") {
+			fmt.Println(line)
+		}
+	}
+	// Output:
+	// found 25 of 25 keywords
+	// h := uint32(len(s)) * b.v[0]
+	// h ^= uint32(s[0]) * b.v[1]
+	// h += uint32(s[1]) * b.v[2]
+	// h ^= uint32(s[len(s)-1]) * b.v[3]
+	// v         [4]uint32
+	// h := uint32(len(s)) * 1
+	// h += uint32(s[0]) * 3
+	// h *= uint32(s[len(s)-1]) * 1
+}
@@ -0,0 +1,115 @@
+package perfect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// HashWord is a word-at-a-time hash family. It loads the first and last 8 bytes of the key
+// as little-endian words, zero padded for keys shorter than 8 bytes, and mixes them with
+// searched multiplicative constants, folding the high half of each 128-bit product
+// onto its low half:
+//
+//	fhi, flo := bits.Mul64(first, FirstMul)
+//	lhi, llo := bits.Mul64(last+uint64(len(s)), LastMul)
+//	return uint(fhi ^ flo ^ lhi ^ llo)
+//
+// The high halves let every byte of both words affect the low bits used to index
+// the table, so HashWord discriminates far more keys than a [HashSequential] for
+// the same number of instructions. Bytes between the first and last 8 of keys
+// longer than 16 bytes do not affect the hash.
+type HashWord struct {
+	FirstMul uint64 // Multiplier of the first word. Kept odd.
+	LastMul  uint64 // Multiplier of the last word. Kept odd.
+	// Attempt counts calls to Increment. The search is exhausted when it reaches MaxAttempt.
+	Attempt    uint64
+	MaxAttempt uint64
+}
+
+// Weyl sequence increments by which Increment advances the multipliers. Both are even so multipliers stay odd.
+const (
+	wordFirstStep = 0x9e3779b97f4a7c16
+	wordLastStep  = 0xc2b2ae3d27d4eb50
+)
+
+// ConfigMuls validates hw's configuration, sets zero multipliers to default
+// starting values and makes the multipliers odd.
+func (hw *HashWord) ConfigMuls() error {
+	if hw.MaxAttempt == 0 {
+		return errors.New("word hash MaxAttempt must be set")
+	} else if hw.Attempt >= hw.MaxAttempt {
+		return errors.New("word hash Attempt must be less than MaxAttempt")
+	}
+	if hw.FirstMul == 0 {
+		hw.FirstMul = 0xbf58476d1ce4e5b9
+	}
+	if hw.LastMul == 0 {
+		hw.LastMul = 0x94d049bb133111eb
+	}
+	hw.FirstMul |= 1
+	hw.LastMul |= 1
+	return nil
+}
+
+// Hash computes the hash value for the given string.
+func (hw *HashWord) Hash(dataToHash string) uint {
+	first, last := loadWords(dataToHash)
+	fhi, flo := bits.Mul64(first, hw.FirstMul)
+	lhi, llo := bits.Mul64(last+uint64(len(dataToHash)), hw.LastMul)
+	return uint(fhi ^ flo ^ lhi ^ llo)
+}
+
+// Increment advances the multipliers. Returns true when exhausted.
+func (hw *HashWord) Increment() (done bool) {
+	hw.FirstMul += wordFirstStep
+	hw.LastMul += wordLastStep
+	hw.Attempt++
+	return hw.Attempt >= hw.MaxAttempt
+}
+
+// Clone returns a copy of hw as a [Hash].
+func (hw *HashWord) Clone() Hash {
+	clone := *hw
+	return &clone
+}
+
+// loadWords returns the first and last 8 bytes of s as little-endian words.
+// Both words are s zero padded if s is shorter than 8 bytes.
+func loadWords(s string) (first, last uint64) {
+	if len(s) >= 8 {
+		return load64(s), load64(s[len(s)-8:])
+	}
+	for i := 0; i < len(s); i++ {
+		first |= uint64(s[i]) << (8 * i)
+	}
+	return first, first
+}
+
+// load64 loads the first 8 bytes of s as a little-endian word. The compiler merges the byte loads.
+func load64(s string) uint64 {
+	_ = s[7]
+	return uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+		uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+}
+
+// goImports returns the packages used by the function written by WriteGoFunc.
+func (hw *HashWord) goImports() []string { return []string{"math/bits"} }
+
+// WriteGoFunc writes hw as a Go function named name. See [GoHasher].
+// The function calls [bits.Mul64]; files generated by this package import math/bits.
+func (hw *HashWord) WriteGoFunc(w io.Writer, name string) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "func %s(s string) uint {\n", name)
+	buf.WriteString("var first, last uint64\nif len(s) >= 8 {\n")
+	buf.WriteString("first = uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 | uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56\n")
+	buf.WriteString("t := s[len(s)-8:]\n")
+	buf.WriteString("last = uint64(t[0]) | uint64(t[1])<<8 | uint64(t[2])<<16 | uint64(t[3])<<24 | uint64(t[4])<<32 | uint64(t[5])<<40 | uint64(t[6])<<48 | uint64(t[7])<<56\n")
+	buf.WriteString("} else {\nfor i := 0; i < len(s); i++ {\nfirst |= uint64(s[i]) << (8 * i)\n}\nlast = first\n}\n")
+	fmt.Fprintf(&buf, "fhi, flo := bits.Mul64(first, %#x)\n", hw.FirstMul)
+	fmt.Fprintf(&buf, "lhi, llo := bits.Mul64(last+uint64(len(s)), %#x)\n", hw.LastMul)
+	buf.WriteString("return uint(fhi ^ flo ^ lhi ^ llo)\n}\n")
+	return writeGoSource(w, buf.Bytes())
+}
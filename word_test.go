@@ -0,0 +1,53 @@
+package perfect
+
+import (
+	"fmt"
+	"go/token"
+	"log"
+)
+
+func ExampleHashWord() {
+	var keywords []string
+	for tok := token.Token(0); tok < 256; tok++ {
+		if tok.IsKeyword() {
+			keywords = append(keywords, tok.String())
+		}
+	}
+	hasher := &HashWord{MaxAttempt: 1 << 20}
+	err := hasher.ConfigMuls()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	const tablesizebits = 6
+	attempts, err := phf.Search(hasher, tablesizebits, keywords)
+	if err != nil {
+		log.Fatalln(err, "after", attempts, "attempts")
+	}
+	fmt.Printf("word hash for Go's %d keywords found after %d attempts\n", len(keywords), attempts)
+	// Output:
+	// word hash for Go's 25 keywords found after 11 attempts
+}
+
+func ExampleHashWord_highBytes() {
+	// Go's sized types differ only in their trailing bytes, which load
+	// into the high bytes of the first word.
+	types := []string{
+		"int8", "int16", "int32", "int64", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64", "complex64", "complex128",
+	}
+	hasher := &HashWord{MaxAttempt: 1 << 20}
+	err := hasher.ConfigMuls()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var phf HashFinder
+	const tablesizebits = 4
+	attempts, err := phf.Search(hasher, tablesizebits, types)
+	if err != nil {
+		log.Fatalln(err, "after", attempts, "attempts")
+	}
+	fmt.Printf("word hash for %d sized types in %d slots found after %d attempts\n", len(types), 1<<tablesizebits, attempts)
+	// Output:
+	// word hash for 12 sized types in 16 slots found after 224 attempts
+}